@@ -0,0 +1,12 @@
+data "vpsadmin_dataset_snapshots" "my-vps" {
+  vps = vpsadmin_vps.my-vps.id
+}
+
+locals {
+  # Snapshots are ordered from the oldest to the newest
+  my-vps-snapshots = data.vpsadmin_dataset_snapshots.my-vps.snapshots
+}
+
+output "latest-snapshot" {
+  value = length(local.my-vps-snapshots) > 0 ? local.my-vps-snapshots[length(local.my-vps-snapshots) - 1] : null
+}
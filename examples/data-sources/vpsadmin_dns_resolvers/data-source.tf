@@ -0,0 +1,13 @@
+data "vpsadmin_dns_resolvers" "prague-v4" {
+  location = "Praha"
+  version  = 4
+}
+
+resource "vpsadmin_vps" "my-vps" {
+  location            = "Praha"
+  install_os_template = "ubuntu-20.04-x86_64-vpsadminos-minimal"
+  dns_resolver        = data.vpsadmin_dns_resolvers.prague-v4.resolvers[0].label
+  cpu                 = 2
+  memory              = 2048
+  diskspace           = 20480
+}
@@ -0,0 +1,17 @@
+data "vpsadmin_free_ip_addresses" "prague-v4" {
+  location = "Prague"
+  version  = 4
+  role     = "public_access"
+  limit    = 10
+}
+
+resource "vpsadmin_vps" "my-vps" {
+  # ...
+
+  lifecycle {
+    precondition {
+      condition     = length(data.vpsadmin_free_ip_addresses.prague-v4.addresses) >= 2
+      error_message = "Not enough free public IPv4 addresses in Prague."
+    }
+  }
+}
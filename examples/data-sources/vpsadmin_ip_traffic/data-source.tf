@@ -0,0 +1,16 @@
+data "vpsadmin_ip_traffic" "my-vps" {
+  vps       = 1234
+  date_from = "2026-01-01T00:00:00Z"
+  date_to   = "2026-02-01T00:00:00Z"
+}
+
+output "my-vps-traffic-out" {
+  value = data.vpsadmin_ip_traffic.my-vps.bytes_out
+}
+
+output "my-vps-traffic-by-month" {
+  value = {
+    for m in data.vpsadmin_ip_traffic.my-vps.months :
+    format("%04d-%02d", m.year, m.month) => m.bytes_out
+  }
+}
@@ -0,0 +1,3 @@
+data "vpsadmin_network_interface" "my-vps" {
+  vps_id = 1234
+}
@@ -0,0 +1,9 @@
+data "vpsadmin_networks" "prague-v4" {
+  location = "Prague"
+  version  = 4
+  role     = "public_access"
+}
+
+output "prague-v4-free" {
+  value = sum(data.vpsadmin_networks.prague-v4.networks[*].free)
+}
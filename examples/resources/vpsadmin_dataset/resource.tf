@@ -22,3 +22,20 @@ resource "vpsadmin_dataset" "my-subdataset" {
   name = "vps${vpsadmin_vps.my-vps.id}/my-subdataset"
   refquota = 20 * 1024
 }
+
+# Tune ZFS properties for a database, properties which are not set are
+# inherited from the parent dataset
+resource "vpsadmin_dataset" "my-database" {
+  name = "vps${vpsadmin_vps.my-vps.id}/mysql"
+  refquota = 20 * 1024
+  recordsize = 16 * 1024
+  atime = false
+}
+
+# Create a nested dataset relative to its parent and apply a user namespace
+# map to it
+resource "vpsadmin_dataset" "my-database-logs" {
+  parent_dataset = vpsadmin_dataset.my-database.id
+  name = "logs"
+  user_namespace_map = 1
+}
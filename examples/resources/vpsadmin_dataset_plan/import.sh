@@ -0,0 +1,2 @@
+# Import dataset plan by dataset ID and plan name
+terraform import vpsadmin_dataset_plan.my-vps-backup $dataset_id/daily_backup
@@ -0,0 +1,11 @@
+# Back up the VPS root dataset daily
+resource "vpsadmin_dataset_plan" "my-vps-backup" {
+  vps  = vpsadmin_vps.my-vps.id
+  plan = "daily_backup"
+}
+
+# Back up a VPS subdataset daily
+resource "vpsadmin_dataset_plan" "my-database-backup" {
+  dataset = vpsadmin_dataset.my-database.id
+  plan    = "daily_backup"
+}
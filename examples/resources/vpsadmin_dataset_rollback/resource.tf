@@ -0,0 +1,22 @@
+resource "vpsadmin_dataset_snapshot" "before-upgrade" {
+  vps   = vpsadmin_vps.my-vps.id
+  label = "before-upgrade"
+}
+
+# Roll back the VPS root dataset, the VPS is stopped and started again
+resource "vpsadmin_dataset_rollback" "undo-upgrade" {
+  vps      = vpsadmin_vps.my-vps.id
+  snapshot = vpsadmin_dataset_snapshot.before-upgrade.id
+
+  # Change to roll back again
+  triggers = {
+    attempt = "1"
+  }
+}
+
+# Read the remaining snapshots only after the rollback
+data "vpsadmin_dataset_snapshots" "after-rollback" {
+  vps = vpsadmin_vps.my-vps.id
+
+  depends_on = [vpsadmin_dataset_rollback.undo-upgrade]
+}
@@ -0,0 +1,2 @@
+# Import snapshot by dataset ID and snapshot ID
+terraform import vpsadmin_dataset_snapshot.before-upgrade $dataset_id/$snapshot_id
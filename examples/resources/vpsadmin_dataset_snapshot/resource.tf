@@ -0,0 +1,11 @@
+# Snapshot a dataset before a risky change
+resource "vpsadmin_dataset_snapshot" "before-upgrade" {
+  dataset = vpsadmin_dataset.my-database.id
+  label   = "before-upgrade"
+}
+
+# Snapshot the root dataset of a VPS
+resource "vpsadmin_dataset_snapshot" "my-vps-root" {
+  vps   = vpsadmin_vps.my-vps.id
+  label = "before-upgrade"
+}
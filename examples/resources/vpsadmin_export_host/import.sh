@@ -0,0 +1,2 @@
+# Import export host by export ID and IP address
+terraform import vpsadmin_export_host.web $export_id/10.0.0.10
@@ -0,0 +1,19 @@
+resource "vpsadmin_dataset" "nas-backups" {
+  name           = "nas/backups"
+  export_dataset = true
+
+  # Allow only hosts listed below
+  export_all_vps = false
+}
+
+resource "vpsadmin_export_host" "backup-server" {
+  export     = vpsadmin_dataset.nas-backups.export_id
+  ip_address = vpsadmin_vps.backup-server.private_ipv4_address
+}
+
+resource "vpsadmin_export_host" "web" {
+  export      = vpsadmin_dataset.nas-backups.export_id
+  ip_address  = vpsadmin_vps.web.private_ipv4_address
+  read_write  = false
+  root_squash = true
+}
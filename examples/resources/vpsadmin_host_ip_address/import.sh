@@ -0,0 +1,2 @@
+# Import the host address by its address
+terraform import vpsadmin_host_ip_address.web-v6-mail 2a03:3b40:fe:1::19
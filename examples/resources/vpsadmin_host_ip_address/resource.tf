@@ -0,0 +1,10 @@
+resource "vpsadmin_ip_address" "web-v6" {
+  vps     = vpsadmin_vps.my-vps.id
+  version = 6
+}
+
+# Configure a specific address from the routed IPv6 network on the VPS
+resource "vpsadmin_host_ip_address" "web-v6-mail" {
+  ip_address = vpsadmin_ip_address.web-v6.id
+  address    = cidrhost("${vpsadmin_ip_address.web-v6.address}/64", 25)
+}
@@ -0,0 +1,2 @@
+# Import the reverse record by host IP address
+terraform import vpsadmin_host_ip_reverse_record.mail 198.51.100.10
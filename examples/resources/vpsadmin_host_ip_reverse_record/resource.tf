@@ -0,0 +1,4 @@
+resource "vpsadmin_host_ip_reverse_record" "mail" {
+  host_ip_address = vpsadmin_vps.my-vps.public_ipv4_address
+  reverse_record  = "mail.example.com."
+}
@@ -0,0 +1,6 @@
+# Find IP address ID with vpsfree-client:
+#   vpsfreectl ip_address list -- --vps $vps_id
+terraform import vpsadmin_ip_address.web-v4 $ip_address_id
+
+# Or import the address directly
+terraform import vpsadmin_ip_address.web-v4 198.51.100.10
@@ -0,0 +1,23 @@
+# Assign an additional public IPv4 address from the VPS location
+resource "vpsadmin_ip_address" "web-v4" {
+  vps     = vpsadmin_vps.my-vps.id
+  version = 4
+  role    = "public_access"
+}
+
+# Assign a specific address, e.g. one kept between VPS rebuilds
+resource "vpsadmin_ip_address" "web-v6" {
+  vps     = vpsadmin_vps.my-vps.id
+  address = "2a03:3b40:fe:1::"
+}
+
+# Assign a reserved address, which survives replacement of the VPS
+resource "vpsadmin_ip_reservation" "mail" {
+  location = "Prague"
+  version  = 4
+}
+
+resource "vpsadmin_ip_address" "mail" {
+  vps     = vpsadmin_vps.my-vps.id
+  address = vpsadmin_ip_reservation.mail.address
+}
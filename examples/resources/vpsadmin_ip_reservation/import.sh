@@ -0,0 +1,2 @@
+# Import an address already owned by you
+terraform import vpsadmin_ip_reservation.web 198.51.100.10
@@ -0,0 +1,10 @@
+resource "vpsadmin_ip_reservation" "web" {
+  location = "Prague"
+  version  = 4
+  role     = "public_access"
+}
+
+resource "vpsadmin_ip_address" "web" {
+  vps     = vpsadmin_vps.my-vps.id
+  address = vpsadmin_ip_reservation.web.address
+}
@@ -2,3 +2,6 @@
 #   vpsfreectl vps list
 #   vpsfreectl vps.mount list $vps_id
 terraform import vpsadmin_mount.vps-subdataset $mount_id
+
+# Or import the mount by VPS ID and mountpoint
+terraform import vpsadmin_mount.vps-subdataset $vps_id/mnt/subdataset
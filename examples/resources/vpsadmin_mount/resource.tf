@@ -17,3 +17,14 @@ resource "vpsadmin_mount" "vps-subdataset" {
   dataset = vpsadmin_dataset.my-subdataset.id
   mountpoint = "/mnt/subdataset"
 }
+
+# Mount last night's backup of the subdataset read-only
+data "vpsadmin_dataset_snapshots" "my-subdataset" {
+  dataset = vpsadmin_dataset.my-subdataset.id
+}
+
+resource "vpsadmin_mount" "vps-subdataset-backup" {
+  vps = vpsadmin_vps.my-vps.id
+  snapshot = data.vpsadmin_dataset_snapshots.my-subdataset.snapshots[length(data.vpsadmin_dataset_snapshots.my-subdataset.snapshots) - 1].id
+  mountpoint = "/mnt/backup"
+}
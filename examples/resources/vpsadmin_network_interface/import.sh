@@ -0,0 +1,3 @@
+# Find network interface ID with vpsfree-client:
+#   vpsfreectl network_interface list -- --vps $vps_id
+terraform import vpsadmin_network_interface.my-vps $network_interface_id
@@ -0,0 +1,6 @@
+resource "vpsadmin_network_interface" "my-vps" {
+  vps    = vpsadmin_vps.my-vps.id
+  name   = "eth0"
+  max_tx = 100000000
+  max_rx = 100000000
+}
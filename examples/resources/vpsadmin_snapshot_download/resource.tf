@@ -0,0 +1,20 @@
+resource "vpsadmin_dataset_snapshot" "weekly" {
+  dataset = vpsadmin_dataset.my-database.id
+  label   = "weekly"
+}
+
+# Download the snapshot as a tar.gz archive
+resource "vpsadmin_snapshot_download" "weekly" {
+  snapshot = vpsadmin_dataset_snapshot.weekly.id
+
+  timeouts {
+    create = "1h"
+  }
+}
+
+output "weekly-backup" {
+  value = {
+    url       = vpsadmin_snapshot_download.weekly.url
+    sha256sum = vpsadmin_snapshot_download.weekly.sha256sum
+  }
+}
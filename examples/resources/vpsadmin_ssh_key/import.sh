@@ -2,3 +2,6 @@
 #   vpsfreectl user current -o id
 #   vpsfreectl user.public_key list $user_id
 terraform import vpsadmin_ssh_key.my-key $key_id
+
+# Or import the key by its label
+terraform import vpsadmin_ssh_key.my-key label:my-key
@@ -1,2 +1,5 @@
 terraform import vpsadmin_vps.my-vps $vps_id
 terraform import vpsadmin_vps.my-vps 1234
+terraform import vpsadmin_vps.my-vps hostname:my-vps
+
+# Soft-deleted VPS cannot be imported, it has to be restored in vpsAdmin first
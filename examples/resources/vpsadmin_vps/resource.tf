@@ -27,4 +27,17 @@ resource "vpsadmin_vps" "my-vps" {
   ssh_keys = [
     vpsadmin_ssh_key.my-key.id,
   ]
+
+  # Allow maintenance only on weekend nights, other days are closed
+  maintenance_window {
+    weekday   = 6 # Saturday
+    opens_at  = "01:00"
+    closes_at = "06:00"
+  }
+
+  maintenance_window {
+    weekday   = 0 # Sunday
+    opens_at  = "01:00"
+    closes_at = "06:00"
+  }
 }
@@ -2,9 +2,15 @@ package vpsadmin
 
 import (
 	"fmt"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
 )
 
+// datasetProperties are ZFS properties configurable on vpsadmin_dataset,
+// which can be inherited from the parent dataset
+var datasetProperties = []string{"compression", "recordsize", "atime", "relatime", "sync"}
+
 func datasetShow(api *client.Client, id int) (*client.ActionDatasetShowOutput, error) {
 	show := api.Dataset.Show.Prepare()
 	show.SetPathParamInt("dataset_id", int64(id))
@@ -39,3 +45,178 @@ func datasetShow(api *client.Client, id int) (*client.ActionDatasetShowOutput, e
 
 	return resp.Output, nil
 }
+
+func validateDatasetRecordsize(v interface{}, k string) (ws []string, es []error) {
+	size := v.(int)
+
+	if size < 4096 || size > 1048576 || size&(size-1) != 0 {
+		es = append(es, fmt.Errorf("%q must be a power of two from 4096 to 1048576, got %d", k, size))
+	}
+
+	return
+}
+
+// datasetPropertyConfigured returns true if property name is set in config
+func datasetPropertyConfigured(config cty.Value, name string) bool {
+	return !config.IsNull() && config.IsKnown() && !config.GetAttr(name).IsNull()
+}
+
+// datasetPropertyValue returns value of property name in the format used by
+// the dataset schema
+func datasetPropertyValue(ds *client.ActionDatasetShowOutput, name string) interface{} {
+	switch name {
+	case "compression":
+		return ds.Compression
+	case "recordsize":
+		return int(ds.Recordsize)
+	case "atime":
+		return ds.Atime
+	case "relatime":
+		return ds.Relatime
+	case "sync":
+		return ds.Sync
+	}
+
+	return nil
+}
+
+func setDatasetCreateProperty(input *client.ActionDatasetCreateInput, name string, v interface{}) {
+	switch name {
+	case "compression":
+		input.SetCompression(v.(bool))
+	case "recordsize":
+		input.SetRecordsize(int64(v.(int)))
+	case "atime":
+		input.SetAtime(v.(bool))
+	case "relatime":
+		input.SetRelatime(v.(bool))
+	case "sync":
+		input.SetSync(v.(string))
+	}
+}
+
+func setDatasetUpdateProperty(input *client.ActionDatasetUpdateInput, name string, v interface{}) {
+	switch name {
+	case "compression":
+		input.SetCompression(v.(bool))
+	case "recordsize":
+		input.SetRecordsize(int64(v.(int)))
+	case "atime":
+		input.SetAtime(v.(bool))
+	case "relatime":
+		input.SetRelatime(v.(bool))
+	case "sync":
+		input.SetSync(v.(string))
+	}
+}
+
+func inheritDatasetProperty(api *client.Client, id int64, name string) error {
+	log.Printf("[INFO] Inheriting property %s of dataset %d", name, id)
+
+	inherit := api.Dataset.Inherit.Prepare()
+	inherit.SetPathParamInt("dataset_id", id)
+
+	input := inherit.NewInput()
+	input.SetProperty(name)
+
+	resp, err := inherit.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Dataset property inheritance failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Dataset property inheritance failed: %v", err)
+	}
+
+	return nil
+}
+
+func datasetSnapshotList(api *client.Client, datasetId int64) ([]*client.ActionDatasetSnapshotIndexOutput, error) {
+	var ret []*client.ActionDatasetSnapshotIndexOutput
+
+	for offset := int64(0); ; offset += apiPageLimit {
+		list := api.Dataset.Snapshot.Index.Prepare()
+		list.SetPathParamInt("dataset_id", datasetId)
+
+		input := list.NewInput()
+		input.SetOffset(offset)
+		input.SetLimit(apiPageLimit)
+
+		resp, err := list.Call()
+
+		if err != nil {
+			return nil, err
+		} else if !resp.Status {
+			return nil, fmt.Errorf("Failed to list snapshots: %s", resp.Message)
+		}
+
+		ret = append(ret, resp.Output...)
+
+		if len(resp.Output) < apiPageLimit {
+			return ret, nil
+		}
+	}
+}
+
+// findDatasetSnapshot returns nil if the snapshot does not exist
+func findDatasetSnapshot(api *client.Client, datasetId, snapshotId int64) (*client.ActionDatasetSnapshotIndexOutput, error) {
+	snapshots, err := datasetSnapshotList(api, datasetId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snap := range snapshots {
+		if snap.Id == snapshotId {
+			return snap, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func rollbackDatasetSnapshot(api *client.Client, datasetId, snapshotId int64) error {
+	log.Printf("[INFO] Rolling back dataset %d to snapshot %d", datasetId, snapshotId)
+
+	rollback := api.Dataset.Snapshot.Rollback.Prepare()
+	rollback.SetPathParamInt("dataset_id", datasetId)
+	rollback.SetPathParamInt("snapshot_id", snapshotId)
+
+	resp, err := rollback.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Snapshot rollback failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Snapshot rollback failed: %v", err)
+	}
+
+	return nil
+}
+
+func setDatasetUserNamespaceMap(api *client.Client, id int64, mapId int64) error {
+	update := api.Dataset.Update.Prepare()
+	update.SetPathParamInt("dataset_id", id)
+
+	input := update.NewInput()
+	input.SetUserNamespaceMap(mapId)
+
+	resp, err := update.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Dataset user namespace map change failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Dataset user namespace map change failed: %v", err)
+	}
+
+	return nil
+}
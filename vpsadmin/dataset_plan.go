@@ -0,0 +1,147 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"strings"
+)
+
+func getEnvironmentIdByLabel(api *client.Client, label string) (int64, error) {
+	list := api.Environment.List.Prepare()
+	resp, err := list.Call()
+
+	if err != nil {
+		return 0, err
+	} else if !resp.Status {
+		return 0, fmt.Errorf("Failed to list environments: %s", resp.Message)
+	}
+
+	for _, env := range resp.Output {
+		if env.Label == label {
+			return env.Id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Environment with label '%s' not found", label)
+}
+
+// getVpsEnvironmentId returns ID of the environment the VPS is in
+func getVpsEnvironmentId(vps *client.ActionVpsShowOutput) (int64, error) {
+	if vps.Node == nil || vps.Node.Location == nil || vps.Node.Location.Environment == nil {
+		return 0, fmt.Errorf("Unable to find environment of VPS %d", vps.Id)
+	}
+
+	return vps.Node.Location.Environment.Id, nil
+}
+
+// getDatasetEnvironmentId returns ID of the environment of the VPS which
+// the dataset belongs to
+func getDatasetEnvironmentId(api *client.Client, datasetId int64) (int64, error) {
+	ds, err := datasetShow(api, int(datasetId))
+	if err != nil {
+		return 0, err
+	}
+
+	if ds.Vps == nil {
+		return 0, fmt.Errorf(
+			"Dataset %d does not belong to a VPS, set environment to find its plans",
+			datasetId,
+		)
+	}
+
+	vps, err := vpsShow(api, int(ds.Vps.Id))
+	if err != nil {
+		return 0, err
+	}
+
+	return getVpsEnvironmentId(vps)
+}
+
+func environmentDatasetPlanList(api *client.Client, envId int64) ([]*client.ActionEnvironmentDatasetPlanIndexOutput, error) {
+	list := api.Environment.DatasetPlan.Index.Prepare()
+	list.SetPathParamInt("environment_id", envId)
+	list.SetMetaInput(&client.ActionEnvironmentDatasetPlanIndexMetaGlobalInput{
+		Includes: "dataset_plan",
+	})
+	list.MetaInput.SelectParameters("Includes")
+
+	input := list.NewInput()
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list environment dataset plans: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func findEnvironmentDatasetPlan(api *client.Client, envId int64, name string) (*client.ActionEnvironmentDatasetPlanIndexOutput, error) {
+	plans, err := environmentDatasetPlanList(api, envId)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(plans))
+
+	for _, plan := range plans {
+		if plan.DatasetPlan == nil {
+			continue
+		}
+
+		if plan.DatasetPlan.Name == name {
+			return plan, nil
+		}
+
+		names = append(names, plan.DatasetPlan.Name)
+	}
+
+	return nil, fmt.Errorf(
+		"Dataset plan '%s' not found, available plans: %s",
+		name,
+		strings.Join(names, ", "),
+	)
+}
+
+func datasetPlanList(api *client.Client, datasetId int64) ([]*client.ActionDatasetPlanIndexOutput, error) {
+	list := api.Dataset.Plan.Index.Prepare()
+	list.SetPathParamInt("dataset_id", datasetId)
+	list.SetMetaInput(&client.ActionDatasetPlanIndexMetaGlobalInput{
+		Includes: "environment_dataset_plan__dataset_plan",
+	})
+	list.MetaInput.SelectParameters("Includes")
+
+	input := list.NewInput()
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list dataset plans: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+// datasetPlanNames returns names of plans active on the dataset
+func datasetPlanNames(api *client.Client, datasetId int64) ([]string, error) {
+	plans, err := datasetPlanList(api, datasetId)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0, len(plans))
+
+	for _, plan := range plans {
+		if envPlan := plan.EnvironmentDatasetPlan; envPlan != nil && envPlan.DatasetPlan != nil {
+			ret = append(ret, envPlan.DatasetPlan.Name)
+		}
+	}
+
+	return ret, nil
+}
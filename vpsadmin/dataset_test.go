@@ -1,10 +1,15 @@
 package vpsadmin
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
 )
 
@@ -20,6 +25,15 @@ func TestDataSourceDatasetReadMapsExportFields(t *testing.T) {
 				Id:   77,
 				Name: "tank/app",
 			})
+		case "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{
+				{
+					Id: 3,
+					EnvironmentDatasetPlan: &client.ActionEnvironmentDatasetPlanShowOutput{
+						DatasetPlan: &client.ActionDatasetPlanShowOutput{Name: "daily_backup"},
+					},
+				},
+			})
 		case "/v7.0/datasets/77":
 			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
 				Id:          77,
@@ -68,9 +82,12 @@ func TestDataSourceDatasetReadMapsExportFields(t *testing.T) {
 	assertResourceValue(t, d, "atime", false)
 	assertResourceValue(t, d, "relatime", true)
 	assertResourceValue(t, d, "sync", "standard")
+	assertResourceValue(t, d, "plans.#", 1)
+	assertResourceValue(t, d, "plans.0", "daily_backup")
 	assertResourceValue(t, d, "export_dataset", true)
 	assertResourceValue(t, d, "export_id", 88)
 	assertResourceValue(t, d, "export_enable", false)
+	assertResourceValue(t, d, "export_all_vps", true)
 	assertResourceValue(t, d, "export_root_squash", true)
 	assertResourceValue(t, d, "export_read_write", false)
 	assertResourceValue(t, d, "export_sync", false)
@@ -81,6 +98,8 @@ func TestDataSourceDatasetReadMapsExportFields(t *testing.T) {
 func TestResourceDatasetReadMapsFetchedExportFields(t *testing.T) {
 	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
+		case "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
 		case "/v7.0/datasets/77":
 			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
 				Id:     77,
@@ -114,15 +133,17 @@ func TestResourceDatasetReadMapsFetchedExportFields(t *testing.T) {
 
 func TestResourceDatasetReadClearsMissingExport(t *testing.T) {
 	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/v7.0/datasets/77" {
+		switch r.URL.Path {
+		case "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
+		case "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
+				Id:   77,
+				Name: "tank/app",
+			})
+		default:
 			http.NotFound(w, r)
-			return
 		}
-
-		writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
-			Id:   77,
-			Name: "tank/app",
-		})
 	})
 
 	d := schema.TestResourceDataRaw(t, resourceDataset().Schema, map[string]interface{}{
@@ -150,6 +171,7 @@ func testExport(id int64) *client.ActionExportShowOutput {
 	return &client.ActionExportShowOutput{
 		Id:            id,
 		Enabled:       false,
+		AllVps:        true,
 		RootSquash:    true,
 		Rw:            false,
 		Sync:          false,
@@ -157,3 +179,390 @@ func testExport(id int64) *client.ActionExportShowOutput {
 		HostIpAddress: &client.ActionHostIpAddressShowOutput{Addr: "192.0.2.55"},
 	}
 }
+
+func TestResourceDatasetDeleteRespectsDeletionProtection(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDataset().Schema, map[string]interface{}{
+		"name":                "app",
+		"deletion_protection": true,
+	})
+	d.SetId("77")
+
+	err := resourceDatasetDelete(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "protected against deletion") {
+		t.Fatalf("resourceDatasetDelete() error = %v, want deletion protection error", err)
+	}
+}
+
+func TestResourceDatasetDiffInheritsUnsetProperties(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/datasets/76" {
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
+			Id:          76,
+			Compression: false,
+			Recordsize:  131072,
+			Atime:       true,
+			Relatime:    false,
+			Sync:        "standard",
+		})
+	})
+
+	state := &terraform.InstanceState{
+		ID: "77",
+		Attributes: map[string]string{
+			"id":             "77",
+			"name":           "db",
+			"parent_dataset": "76",
+			"compression":    "true",
+			"recordsize":     "131072",
+			"atime":          "true",
+			"relatime":       "false",
+			"sync":           "standard",
+		},
+		RawConfig: testRawConfig(resourceDataset(), map[string]cty.Value{
+			"name":       cty.StringVal("db"),
+			"recordsize": cty.NumberIntVal(16384),
+			"atime":      cty.False,
+		}),
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":       "db",
+		"recordsize": 16384,
+		"atime":      false,
+	})
+
+	diff, err := resourceDataset().Diff(context.Background(), state, config, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attr := diff.Attributes["recordsize"]; attr == nil || attr.New != "16384" {
+		t.Fatalf("recordsize diff = %+v, want 16384", attr)
+	}
+
+	// Compression is set locally, it is going to be inherited from the parent
+	if attr := diff.Attributes["compression"]; attr == nil || attr.New != "false" {
+		t.Fatalf("compression diff = %+v, want false from the parent", attr)
+	}
+
+	// Properties which are already the same as in the parent are not changed
+	for _, name := range []string{"relatime", "sync"} {
+		if attr := diff.Attributes[name]; attr != nil {
+			t.Fatalf("%s diff = %+v, want none", name, attr)
+		}
+	}
+}
+
+func TestResourceDatasetDiffKeepsPropertiesOfTopLevelDataset(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	state := &terraform.InstanceState{
+		ID: "77",
+		Attributes: map[string]string{
+			"id":          "77",
+			"name":        "tank",
+			"compression": "false",
+			"recordsize":  "16384",
+			"atime":       "false",
+			"relatime":    "true",
+			"sync":        "disabled",
+		},
+		RawConfig: testRawConfig(resourceDataset(), map[string]cty.Value{
+			"name": cty.StringVal("tank"),
+		}),
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "tank",
+	})
+
+	diff, err := resourceDataset().Diff(context.Background(), state, config, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range datasetProperties {
+		if attr := diff.Attributes[name]; attr != nil {
+			t.Fatalf("%s diff = %+v, want none", name, attr)
+		}
+	}
+}
+
+func TestResourceDatasetUpdateSetsAndInheritsProperties(t *testing.T) {
+	var inherited []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v7.0/datasets/77" && r.Method == http.MethodPut:
+			body := readRequestBody(t, r)
+			if !strings.Contains(body, `"recordsize":16384`) {
+				t.Errorf("update body = %s", body)
+			}
+			if strings.Contains(body, "compression") {
+				t.Errorf("update body must not contain inherited properties: %s", body)
+			}
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetUpdateOutput{})
+		case r.URL.Path == "/v7.0/datasets/77/inherit":
+			body := readRequestBody(t, r)
+			inherited = append(inherited, body)
+			writeAPIResponse(t, w, "dataset", map[string]interface{}{})
+		case r.URL.Path == "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
+		case r.URL.Path == "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 77, Name: "tank/db"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d, err := schema.InternalMap(resourceDataset().Schema).Data(
+		&terraform.InstanceState{
+			ID: "77",
+			Attributes: map[string]string{
+				"name":           "db",
+				"parent_dataset": "76",
+				"compression":    "true",
+				"recordsize":     "131072",
+			},
+		},
+		&terraform.InstanceDiff{
+			Attributes: map[string]*terraform.ResourceAttrDiff{
+				"recordsize": {
+					Old: "131072",
+					New: "16384",
+				},
+				"compression": {
+					Old: "true",
+					New: "false",
+				},
+			},
+			RawConfig: testRawConfig(resourceDataset(), map[string]cty.Value{
+				"name":       cty.StringVal("db"),
+				"recordsize": cty.NumberIntVal(16384),
+			}),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resourceDatasetUpdate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inherited) != 1 || !strings.Contains(inherited[0], `"property":"compression"`) {
+		t.Fatalf("inherited = %v, want compression", inherited)
+	}
+}
+
+func TestResourceDatasetReadMapsHierarchy(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
+		case "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{
+				Id:               77,
+				Name:             "101/data/db",
+				Parent:           &client.ActionDatasetShowOutput{Id: 76},
+				Vps:              &client.ActionVpsShowOutput{Id: 101},
+				UserNamespaceMap: &client.ActionUserNamespaceMapShowOutput{Id: 5},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDataset().Schema, map[string]interface{}{
+		"name": "db",
+	})
+	d.SetId("77")
+
+	if err := resourceDatasetRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "parent_dataset", 76)
+	assertResourceValue(t, d, "vps", 101)
+	assertResourceValue(t, d, "user_namespace_map", 5)
+}
+
+func TestResourceDatasetDiffRequiresExistingParentDataset(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/datasets/76":
+			writeAPIError(t, w, "object not found")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	state := &terraform.InstanceState{
+		RawConfig: testRawConfig(resourceDataset(), map[string]cty.Value{
+			"name":           cty.StringVal("db"),
+			"parent_dataset": cty.NumberIntVal(76),
+		}),
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":           "db",
+		"parent_dataset": 76,
+	})
+
+	_, err := resourceDataset().Diff(context.Background(), state, config, cfg)
+	if err == nil || !strings.Contains(err.Error(), "Parent dataset 76 not found") {
+		t.Fatalf("err = %v, want missing parent dataset", err)
+	}
+}
+
+func TestValidateDatasetRecordsize(t *testing.T) {
+	for _, size := range []int{4096, 16384, 1048576} {
+		if _, es := validateDatasetRecordsize(size, "recordsize"); len(es) > 0 {
+			t.Fatalf("recordsize %d: %v", size, es)
+		}
+	}
+
+	for _, size := range []int{512, 12288, 2097152} {
+		if _, es := validateDatasetRecordsize(size, "recordsize"); len(es) == 0 {
+			t.Fatalf("recordsize %d passed validation", size)
+		}
+	}
+}
+
+func TestResourceDatasetUserNamespaceMapIsRemovedOnlyWhenConfigured(t *testing.T) {
+	t.Parallel()
+
+	state := &terraform.InstanceState{
+		ID: "77",
+		Attributes: map[string]string{
+			"name":               "db",
+			"user_namespace_map": "5",
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config map[string]interface{}
+		change bool
+	}{
+		{"unset", map[string]interface{}{"name": "db"}, false},
+		{"zero", map[string]interface{}{"name": "db", "user_namespace_map": 0}, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			diff, err := resourceDataset().Diff(
+				context.Background(),
+				state,
+				terraform.NewResourceConfigRaw(tc.config),
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var attr *terraform.ResourceAttrDiff
+			if diff != nil {
+				attr = diff.Attributes["user_namespace_map"]
+			}
+
+			if tc.change && (attr == nil || attr.New != "0") {
+				t.Fatalf("user_namespace_map diff = %#v, want removal", attr)
+			} else if !tc.change && attr != nil {
+				t.Fatalf("user_namespace_map diff = %#v, want none", attr)
+			}
+		})
+	}
+}
+
+func TestResourceDatasetUpdateRemovesUserNamespaceMap(t *testing.T) {
+	var updateBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v7.0/datasets/77" && r.Method == http.MethodPut:
+			updateBody = readRequestBody(t, r)
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetUpdateOutput{})
+		case r.URL.Path == "/v7.0/datasets/77/plans":
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
+		case r.URL.Path == "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 77, Name: "tank/db"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(t, resourceDataset().Schema, "77", map[string]string{
+		"name":               "db",
+		"user_namespace_map": "5",
+	}, map[string]*terraform.ResourceAttrDiff{
+		"user_namespace_map": {Old: "5", New: "0"},
+	})
+
+	if err := resourceDatasetUpdate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(updateBody, `"user_namespace_map":null`) {
+		t.Fatalf("update body = %s, want user_namespace_map null", updateBody)
+	}
+}
+
+func TestDataSourceDatasetSnapshotsSortsAllPages(t *testing.T) {
+	var offsets []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/datasets/77/snapshots" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "snapshot[limit]", strconv.Itoa(apiPageLimit))
+
+		offset := r.URL.Query().Get("snapshot[offset]")
+		offsets = append(offsets, offset)
+
+		if offset != "0" {
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: int64(apiPageLimit + 1), Name: "newest"},
+			})
+			return
+		}
+
+		snapshots := make([]*client.ActionDatasetSnapshotIndexOutput, apiPageLimit)
+		for i := range snapshots {
+			snapshots[i] = &client.ActionDatasetSnapshotIndexOutput{Id: int64(apiPageLimit - i)}
+		}
+
+		writeAPIResponse(t, w, "snapshots", snapshots)
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceDatasetSnapshots().Schema, map[string]interface{}{
+		"dataset": 77,
+	})
+
+	if err := dataSourceDatasetSnapshotsRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(offsets) != 2 || offsets[1] != strconv.Itoa(apiPageLimit) {
+		t.Fatalf("offsets = %v", offsets)
+	}
+	assertResourceValue(t, d, "snapshots.#", apiPageLimit+1)
+	assertResourceValue(t, d, "snapshots.0.id", 1)
+	assertResourceValue(t, d, "snapshots."+strconv.Itoa(apiPageLimit)+".name", "newest")
+}
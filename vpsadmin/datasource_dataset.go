@@ -71,6 +71,27 @@ func dataSourceDataset() *schema.Resource {
 				Description: "Sync mode",
 				Computed:    true,
 			},
+			"parent_dataset": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the parent dataset",
+				Computed:    true,
+			},
+			"user_namespace_map": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the user namespace map applied to the dataset",
+				Computed:    true,
+			},
+			"vps": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the VPS the dataset belongs to, 0 for NAS datasets",
+				Computed:    true,
+			},
+			"plans": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Names of active dataset plans, see vpsadmin_dataset_plan",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"export_dataset": &schema.Schema{
 				Type:        schema.TypeBool,
 				Description: "Export dataset over NFS",
@@ -96,6 +117,11 @@ func dataSourceDataset() *schema.Resource {
 				Description: "Read-write access by default",
 				Computed:    true,
 			},
+			"export_all_vps": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "All VPS of the user can mount the export",
+				Computed:    true,
+			},
 			"export_sync": &schema.Schema{
 				Type:        schema.TypeBool,
 				Description: "Server will reply only after changes were committed",
@@ -138,6 +164,19 @@ func dataSourceDatasetRead(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(strconv.Itoa(int(ds.Id)))
 	d.Set("full_name", ds.Name)
+
+	if ds.Parent != nil {
+		d.Set("parent_dataset", ds.Parent.Id)
+	}
+
+	if ds.UserNamespaceMap != nil {
+		d.Set("user_namespace_map", ds.UserNamespaceMap.Id)
+	}
+
+	if ds.Vps != nil {
+		d.Set("vps", ds.Vps.Id)
+	}
+
 	d.Set("used", ds.Used)
 	d.Set("referenced", ds.Referenced)
 	d.Set("avail", ds.Avail)
@@ -149,10 +188,18 @@ func dataSourceDatasetRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("relatime", ds.Relatime)
 	d.Set("sync", ds.Sync)
 
+	plans, err := datasetPlanNames(api, ds.Id)
+	if err != nil {
+		return err
+	}
+
+	d.Set("plans", plans)
+
 	if ds.Export != nil {
 		d.Set("export_dataset", true)
 		d.Set("export_id", ds.Export.Id)
 		d.Set("export_enable", ds.Export.Enabled)
+		d.Set("export_all_vps", ds.Export.AllVps)
 		d.Set("export_root_squash", ds.Export.RootSquash)
 		d.Set("export_read_write", ds.Export.Rw)
 		d.Set("export_sync", ds.Export.Sync)
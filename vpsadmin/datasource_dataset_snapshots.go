@@ -0,0 +1,124 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sort"
+	"strconv"
+)
+
+func dataSourceDatasetSnapshots() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDatasetSnapshotsRead,
+
+		Description: `
+Lists snapshots of a dataset or a VPS root dataset, including snapshots kept
+only in backups. Snapshots are ordered from the oldest to the newest.
+
+The vpsAdmin API does not tell users whether a snapshot is still present
+on the primary pool or only in backups, so the list does not distinguish them.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"dataset": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Dataset ID",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"vps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "VPS ID, lists snapshots of the VPS root dataset",
+				Optional:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"label": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "List only snapshots with this label",
+				Optional:    true,
+			},
+			"snapshots": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Snapshots of the dataset ordered from the oldest to the newest, it is not known which of them are only in backups",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Snapshot ID",
+							Computed:    true,
+						},
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Snapshot name",
+							Computed:    true,
+						},
+						"label": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Snapshot label",
+							Computed:    true,
+						},
+						"created_at": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Time of creation",
+							Computed:    true,
+						},
+						"history_id": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "History identifier of the dataset at the time of the snapshot",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDatasetSnapshotsRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	datasetId := int64(d.Get("dataset").(int))
+
+	if v, ok := d.GetOk("vps"); ok {
+		id, err := getVpsRootDatasetId(api, v.(int))
+		if err != nil {
+			return err
+		}
+
+		datasetId = id
+	}
+
+	snapshots, err := datasetSnapshotList(api, datasetId)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Id < snapshots[j].Id
+	})
+
+	label := d.Get("label").(string)
+	ret := make([]interface{}, 0, len(snapshots))
+
+	for _, snap := range snapshots {
+		if label != "" && snap.Label != label {
+			continue
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"id":         int(snap.Id),
+			"name":       snap.Name,
+			"label":      snap.Label,
+			"created_at": snap.CreatedAt,
+			"history_id": int(snap.HistoryId),
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", strconv.FormatInt(datasetId, 10), label))
+	d.Set("dataset", datasetId)
+	d.Set("snapshots", ret)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceDnsResolvers() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDnsResolversRead,
+
+		Description: "Lists DNS resolvers which can be used as *dns_resolver* of *vpsadmin_vps*.",
+
+		Schema: map[string]*schema.Schema{
+			"location": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "List only resolvers usable in location with this label",
+				Optional:    true,
+			},
+			"version": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "List only resolvers with this IP version, 4 or 6",
+				Optional:     true,
+				ValidateFunc: validation.IntInSlice([]int{4, 6}),
+			},
+			"universal": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "List only universal resolvers if true, or only location-specific resolvers if false",
+				Optional:    true,
+			},
+			"resolvers": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Matching DNS resolvers",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Resolver ID",
+							Computed:    true,
+						},
+						"label": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Resolver label, usable as dns_resolver of vpsadmin_vps",
+							Computed:    true,
+						},
+						"addresses": &schema.Schema{
+							Type:        schema.TypeList,
+							Description: "Resolver IP addresses",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"version": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "IP version",
+							Computed:    true,
+						},
+						"universal": &schema.Schema{
+							Type:        schema.TypeBool,
+							Description: "Usable in all locations",
+							Computed:    true,
+						},
+						"location": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Location label of location-specific resolvers",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDnsResolversRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	resolvers, err := dnsResolverList(api)
+	if err != nil {
+		return err
+	}
+
+	location := d.Get("location").(string)
+	version := d.Get("version").(int)
+	universal, filterUniversal := d.GetOkExists("universal")
+
+	ret := make([]interface{}, 0, len(resolvers))
+
+	for _, resolver := range resolvers {
+		if location != "" && !isDnsResolverAvailableIn(resolver, location) {
+			continue
+		} else if version != 0 && int(resolver.IpVersion) != version {
+			continue
+		} else if filterUniversal && resolver.IsUniversal != universal.(bool) {
+			continue
+		}
+
+		v := map[string]interface{}{
+			"id":        int(resolver.Id),
+			"label":     resolver.Label,
+			"addresses": splitDnsResolverAddresses(resolver.IpAddr),
+			"version":   int(resolver.IpVersion),
+			"universal": resolver.IsUniversal,
+			"location":  "",
+		}
+
+		if resolver.Location != nil {
+			v["location"] = resolver.Location.Label
+		}
+
+		ret = append(ret, v)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d:%v:%v", location, version, filterUniversal, universal))
+	d.Set("resolvers", ret)
+
+	return nil
+}
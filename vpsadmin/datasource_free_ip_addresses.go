@@ -0,0 +1,96 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFreeIpAddresses() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceFreeIpAddressesRead,
+
+		Description: "Lists IP addresses which are not assigned to any network interface and can be added to a VPS.",
+
+		Schema: networkFilterSchema(map[string]*schema.Schema{
+			"limit": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of listed addresses",
+				Optional:     true,
+				Default:      apiPageLimit,
+				ValidateFunc: validation.IntBetween(1, apiPageLimit),
+			},
+			"addresses": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Free IP addresses",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "IP address ID",
+							Computed:    true,
+						},
+						"address": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "IP address",
+							Computed:    true,
+						},
+						"prefix": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Prefix of the routed address",
+							Computed:    true,
+						},
+						"network": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "ID of the network the address belongs to",
+							Computed:    true,
+						},
+						"owned": &schema.Schema{
+							Type:        schema.TypeBool,
+							Description: "Whether the address is owned by the current user",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceFreeIpAddressesRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	filter, err := getNetworkFilter(api, d)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := freeIpAddressList(api, filter, d.Get("limit").(int))
+	if err != nil {
+		return err
+	}
+
+	ret := make([]interface{}, 0, len(addrs))
+
+	for _, ip := range addrs {
+		v := map[string]interface{}{
+			"id":      int(ip.Id),
+			"address": ip.Addr,
+			"prefix":  int(ip.Prefix),
+			"network": 0,
+			"owned":   ip.User != nil,
+		}
+
+		if ip.Network != nil {
+			v["network"] = int(ip.Network.Id)
+		}
+
+		ret = append(ret, v)
+	}
+
+	d.SetId(fmt.Sprintf("%d:%d:%s:%s", filter.locationId, filter.ipVersion, filter.role, filter.purpose))
+	d.Set("addresses", ret)
+
+	return nil
+}
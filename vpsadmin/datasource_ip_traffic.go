@@ -0,0 +1,196 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceIpTraffic() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceIpTrafficRead,
+
+		Description: `
+Aggregated traffic accounting of a VPS or an IP address. Counters are summed
+over all records within the selected date range, *months* contains the same
+traffic summed by month from vpsAdmin monthly summaries. Records that vpsAdmin
+already summed over all protocols are skipped.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"vps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Count traffic of all addresses of VPS with this ID",
+				Optional:     true,
+				AtLeastOneOf: []string{"vps", "ip_address"},
+			},
+			"ip_address": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Count traffic of this IP address",
+				Optional:     true,
+				ValidateFunc: validation.IsIPAddress,
+				AtLeastOneOf: []string{"vps", "ip_address"},
+			},
+			"protocol": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Count only traffic of this protocol, one of tcp, udp or other",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "other"}, false),
+			},
+			"date_from": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Count traffic since this date, in RFC 3339 format",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"date_to": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Count traffic until this date, in RFC 3339 format",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"packets_in": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Number of incoming packets",
+				Computed:    true,
+			},
+			"packets_out": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Number of outgoing packets",
+				Computed:    true,
+			},
+			"bytes_in": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Number of incoming bytes",
+				Computed:    true,
+			},
+			"bytes_out": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Number of outgoing bytes",
+				Computed:    true,
+			},
+			"protocols": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Counters per protocol",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Protocol name",
+							Computed:    true,
+						},
+						"packets_in": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of incoming packets",
+							Computed:    true,
+						},
+						"packets_out": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of outgoing packets",
+							Computed:    true,
+						},
+						"bytes_in": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of incoming bytes",
+							Computed:    true,
+						},
+						"bytes_out": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of outgoing bytes",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"months": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Counters per month, ordered from the oldest month",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"year": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Year",
+							Computed:    true,
+						},
+						"month": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Month, 1 to 12",
+							Computed:    true,
+						},
+						"packets_in": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of incoming packets",
+							Computed:    true,
+						},
+						"packets_out": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of outgoing packets",
+							Computed:    true,
+						},
+						"bytes_in": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of incoming bytes",
+							Computed:    true,
+						},
+						"bytes_out": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of outgoing bytes",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIpTrafficRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	filter := ipTrafficFilter{
+		vpsId:    int64(d.Get("vps").(int)),
+		protocol: d.Get("protocol").(string),
+		dateFrom: d.Get("date_from").(string),
+		dateTo:   d.Get("date_to").(string),
+	}
+
+	if v, ok := d.GetOk("ip_address"); ok {
+		ip, err := findIpAddressByAddr(api, v.(string))
+		if err != nil {
+			return err
+		}
+
+		filter.ipId = ip.Id
+	}
+
+	records, err := ipTrafficList(api, filter)
+	if err != nil {
+		return err
+	}
+
+	total, protocols := aggregateIpTraffic(records)
+
+	monthly, err := ipTrafficMonthlyList(api, filter)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf(
+		"%d:%d:%s:%s:%s",
+		filter.vpsId,
+		filter.ipId,
+		filter.protocol,
+		filter.dateFrom,
+		filter.dateTo,
+	))
+	d.Set("packets_in", int(total.packetsIn))
+	d.Set("packets_out", int(total.packetsOut))
+	d.Set("bytes_in", int(total.bytesIn))
+	d.Set("bytes_out", int(total.bytesOut))
+	d.Set("protocols", protocols)
+	d.Set("months", aggregateIpTrafficMonthly(monthly))
+
+	return nil
+}
@@ -25,6 +25,11 @@ func dataSourceMount() *schema.Resource {
 				Description: "ID of the mounted dataset",
 				Computed:    true,
 			},
+			"snapshot": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the mounted snapshot, if a snapshot is mounted",
+				Computed:    true,
+			},
 			"mountpoint": &schema.Schema{
 				Type:        schema.TypeString,
 				Description: "Mountpoint inside the VPS",
@@ -59,6 +64,13 @@ func dataSourceMountRead(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(strconv.Itoa(int(mount.Id)))
 	d.Set("dataset", mount.Dataset.Id)
+
+	if mount.Snapshot != nil {
+		d.Set("snapshot", mount.Snapshot.Id)
+	} else {
+		d.Set("snapshot", nil)
+	}
+
 	d.Set("mountpoint", mount.Mountpoint)
 	d.Set("enable", mount.Enabled)
 	d.Set("mode", mount.Mode)
@@ -0,0 +1,58 @@
+package vpsadmin
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+)
+
+func dataSourceNetworkInterface() *schema.Resource {
+	s := networkInterfaceSchema(true)
+	s["vps_id"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Description: "VPS ID",
+		Required:    true,
+	}
+	s["interface_id"] = &schema.Schema{
+		Type:          schema.TypeInt,
+		Description:   "Select the interface by its ID",
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	s["name"].Description = "Interface name as seen inside the VPS, e.g. eth0, can be used to select the interface"
+	s["name"].Optional = true
+
+	return &schema.Resource{
+		Read:   dataSourceNetworkInterfaceRead,
+		Schema: s,
+	}
+}
+
+func dataSourceNetworkInterfaceRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	vps, err := vpsShow(api, d.Get("vps_id").(int))
+	if err != nil {
+		return err
+	}
+
+	netif, err := getVpsNetworkInterface(
+		api,
+		vps.Id,
+		int64(d.Get("interface_id").(int)),
+		d.Get("name").(string),
+	)
+	if err != nil {
+		return err
+	}
+
+	show, err := networkInterfaceShow(api, netif.Id)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(netif.Id, 10))
+	setNetworkInterfaceAttributes(d, show)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceNetworks() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetworksRead,
+
+		Description: "Lists networks available to VPS in vpsAdmin, including their usage.",
+
+		Schema: networkFilterSchema(map[string]*schema.Schema{
+			"networks": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Matching networks",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Network ID",
+							Computed:    true,
+						},
+						"label": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Network label",
+							Computed:    true,
+						},
+						"address": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Network address",
+							Computed:    true,
+						},
+						"prefix": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Network prefix",
+							Computed:    true,
+						},
+						"version": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "IP version",
+							Computed:    true,
+						},
+						"role": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Network role",
+							Computed:    true,
+						},
+						"purpose": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "Network purpose",
+							Computed:    true,
+						},
+						"managed": &schema.Schema{
+							Type:        schema.TypeBool,
+							Description: "Whether addresses of this network are managed by vpsAdmin",
+							Computed:    true,
+						},
+						"split_access": &schema.Schema{
+							Type:        schema.TypeString,
+							Description: "How is the network split between users",
+							Computed:    true,
+						},
+						"split_prefix": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Prefix of addresses the network is split into",
+							Computed:    true,
+						},
+						"size": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of possible addresses in the network",
+							Computed:    true,
+						},
+						"used": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of addresses present in vpsAdmin",
+							Computed:    true,
+						},
+						"assigned": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of addresses assigned to network interfaces",
+							Computed:    true,
+						},
+						"owned": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of addresses owned by users",
+							Computed:    true,
+						},
+						"taken": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of addresses assigned or owned",
+							Computed:    true,
+						},
+						"free": &schema.Schema{
+							Type:        schema.TypeInt,
+							Description: "Number of addresses which are neither assigned nor owned",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceNetworksRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	filter, err := getNetworkFilter(api, d)
+	if err != nil {
+		return err
+	}
+
+	networks, err := networkList(api, filter)
+	if err != nil {
+		return err
+	}
+
+	ret := make([]interface{}, 0, len(networks))
+
+	for _, network := range networks {
+		ret = append(ret, map[string]interface{}{
+			"id":           int(network.Id),
+			"label":        network.Label,
+			"address":      network.Address,
+			"prefix":       int(network.Prefix),
+			"version":      int(network.IpVersion),
+			"role":         network.Role,
+			"purpose":      network.Purpose,
+			"managed":      network.Managed,
+			"split_access": network.SplitAccess,
+			"split_prefix": int(network.SplitPrefix),
+			"size":         int(network.Size),
+			"used":         int(network.Used),
+			"assigned":     int(network.Assigned),
+			"owned":        int(network.Owned),
+			"taken":        int(network.Taken),
+			"free":         int(network.Used - network.Taken),
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%d:%d:%s:%s", filter.locationId, filter.ipVersion, filter.role, filter.purpose))
+	d.Set("networks", ret)
+
+	return nil
+}
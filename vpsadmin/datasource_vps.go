@@ -81,6 +81,10 @@ func dataSourceVps() *schema.Resource {
 				Description: "Primary public IPv6 address",
 				Computed:    true,
 			},
+			"ipv4_addresses":         ipAddressListSchema("All public IPv4 addresses"),
+			"private_ipv4_addresses": ipAddressListSchema("All private IPv4 addresses"),
+			"ipv6_addresses":         ipAddressListSchema("All public IPv6 addresses"),
+			"network_config":         networkConfigSchema(),
 			"feature_fuse": {
 				Type:        schema.TypeBool,
 				Description: "Allow access to FUSE filesystems",
@@ -136,6 +140,16 @@ func dataSourceVpsRead(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
+	hostAddrs, err := getVpsHostIpAddresses(api, vps.Id)
+	if err != nil {
+		return err
+	}
+
+	netifs, err := vpsNetworkInterfaceList(api, vps.Id)
+	if err != nil {
+		return err
+	}
+
 	d.SetId(strconv.Itoa(id))
 	d.Set("location", vps.Node.Location.Label)
 	d.Set("node", vps.Node.DomainName)
@@ -151,9 +165,13 @@ func dataSourceVpsRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("memory", vps.Memory)
 	d.Set("swap", vps.Swap)
 	d.Set("diskspace", ds.Refquota)
-	d.Set("public_ipv4_address", getPrimaryPublicHostIpv4Address(api, vps.Id))
-	d.Set("private_ipv4_address", getPrimaryPrivateHostIpv4Address(api, vps.Id))
-	d.Set("public_ipv6_address", getPrimaryPublicHostIpv6Address(api, vps.Id))
+	d.Set("public_ipv4_address", primaryHostIpAddress(hostAddrs.publicIpv4))
+	d.Set("private_ipv4_address", primaryHostIpAddress(hostAddrs.privateIpv4))
+	d.Set("public_ipv6_address", primaryHostIpAddress(hostAddrs.publicIpv6))
+	d.Set("ipv4_addresses", flattenHostIpAddresses(hostAddrs.publicIpv4))
+	d.Set("private_ipv4_addresses", flattenHostIpAddresses(hostAddrs.privateIpv4))
+	d.Set("ipv6_addresses", flattenHostIpAddresses(hostAddrs.publicIpv6))
+	d.Set("network_config", flattenNetworkConfig(netifs, hostAddrs.all()))
 
 	for _, feature := range features {
 		if isSupportedVpsFeature(feature.Name) {
@@ -2,6 +2,8 @@ package vpsadmin
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -42,6 +44,11 @@ func TestDataSourceVpsReadSetsComputedFields(t *testing.T) {
 			})
 		case "/v7.0/host_ip_addresses":
 			writeAPIResponse(t, w, "host_ip_addresses", hostIPAddressesForQuery(r))
+		case "/v7.0/network_interfaces":
+			assertQueryValue(t, r, "network_interface[vps]", "123")
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 55, Name: "venet0", Type: "venet"},
+			})
 		default:
 			http.NotFound(w, r)
 		}
@@ -68,6 +75,21 @@ func TestDataSourceVpsReadSetsComputedFields(t *testing.T) {
 	assertResourceValue(t, d, "public_ipv4_address", "198.51.100.10")
 	assertResourceValue(t, d, "private_ipv4_address", "10.0.0.10")
 	assertResourceValue(t, d, "public_ipv6_address", "2001:db8::10")
+	assertResourceValue(t, d, "ipv4_addresses.#", 2)
+	assertResourceValue(t, d, "ipv4_addresses.0.address", "198.51.100.10")
+	assertResourceValue(t, d, "ipv4_addresses.0.prefix", 32)
+	assertResourceValue(t, d, "ipv4_addresses.0.network", "198.51.100.0/24")
+	assertResourceValue(t, d, "ipv4_addresses.0.interface", "venet0")
+	assertResourceValue(t, d, "ipv4_addresses.0.reverse_record", "app01.example.com.")
+	assertResourceValue(t, d, "ipv4_addresses.1.address", "198.51.100.11")
+	assertResourceValue(t, d, "private_ipv4_addresses.#", 1)
+	assertResourceValue(t, d, "network_config.#", 1)
+	assertResourceValue(t, d, "network_config.0.name", "venet0")
+	assertResourceValue(t, d, "network_config.0.addresses.#", 1)
+	assertResourceValue(t, d, "network_config.0.addresses.0", "198.51.100.10/32")
+	assertResourceValue(t, d, "network_config.0.routes.0.destination", "198.51.100.10/32")
+	assertResourceValue(t, d, "network_config.0.routes.0.via", "")
+	assertResourceValue(t, d, "ipv6_addresses.#", 1)
 	assertResourceValue(t, d, "feature_fuse", true)
 	assertResourceValue(t, d, "feature_kvm", false)
 	assertResourceValue(t, d, "feature_lxc", false)
@@ -94,6 +116,8 @@ func TestDataSourceVpsReadClearsDnsResolver(t *testing.T) {
 			writeAPIResponse(t, w, "features", []*client.ActionVpsFeatureIndexOutput{})
 		case "/v7.0/host_ip_addresses":
 			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{})
+		case "/v7.0/network_interfaces":
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{})
 		default:
 			http.NotFound(w, r)
 		}
@@ -115,6 +139,37 @@ func TestDataSourceVpsReadClearsDnsResolver(t *testing.T) {
 	}
 }
 
+func TestDataSourceVpsReadReturnsHostIpAddressErrors(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:         123,
+				Dataset:    &client.ActionDatasetShowOutput{Id: 456},
+				Node:       testNode("node-a", "prg"),
+				OsTemplate: &client.ActionOsTemplateShowOutput{Name: "debian-12"},
+			})
+		case "/v7.0/datasets/456":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 456})
+		case "/v7.0/vpses/123/features":
+			writeAPIResponse(t, w, "features", []*client.ActionVpsFeatureIndexOutput{})
+		case "/v7.0/host_ip_addresses":
+			writeAPIError(t, w, "access denied")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceVps().Schema, map[string]interface{}{
+		"vps_id": 123,
+	})
+
+	err := dataSourceVpsRead(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("dataSourceVpsRead() error = %v, want access denied", err)
+	}
+}
+
 func testNode(domainName string, location string) *client.ActionNodeShowOutput {
 	return &client.ActionNodeShowOutput{
 		DomainName: domainName,
@@ -126,14 +181,27 @@ func hostIPAddressesForQuery(r *http.Request) []*client.ActionHostIpAddressIndex
 	q := r.URL.Query()
 	if q.Get("host_ip_address[vps]") != "123" ||
 		q.Get("host_ip_address[assigned]") != "1" ||
-		q.Get("host_ip_address[limit]") != "1" {
+		q.Get("host_ip_address[limit]") != strconv.Itoa(apiPageLimit) {
 		return nil
 	}
 
 	switch {
 	case q.Get("host_ip_address[version]") == "4" &&
 		q.Get("host_ip_address[role]") == "public_access":
-		return []*client.ActionHostIpAddressIndexOutput{{Addr: "198.51.100.10"}}
+		return []*client.ActionHostIpAddressIndexOutput{
+			{
+				Addr:               "198.51.100.10",
+				ReverseRecordValue: "app01.example.com.",
+				IpAddress: &client.ActionIpAddressShowOutput{
+					Id:               9,
+					Addr:             "198.51.100.10",
+					Prefix:           32,
+					Network:          &client.ActionNetworkShowOutput{Address: "198.51.100.0", Prefix: 24},
+					NetworkInterface: &client.ActionNetworkInterfaceShowOutput{Id: 55, Name: "venet0"},
+				},
+			},
+			{Addr: "198.51.100.11"},
+		}
 	case q.Get("host_ip_address[version]") == "4" &&
 		q.Get("host_ip_address[role]") == "private_access":
 		return []*client.ActionHostIpAddressIndexOutput{{Addr: "10.0.0.10"}}
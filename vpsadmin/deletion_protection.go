@@ -0,0 +1,31 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deletionProtectionSchema returns the deletion_protection attribute. The flag
+// is kept only in Terraform state and enforced by the provider, vpsAdmin has
+// no lock against deletion that users could set. Maintenance locks exist, but
+// can be set only by administrators.
+func deletionProtectionSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: fmt.Sprintf("Refuse to destroy the %s, including replacements forced by changed arguments, until set to false. Enforced only by the provider, it is not stored in vpsAdmin", kind),
+		Default:     false,
+		Optional:    true,
+	}
+}
+
+func checkDeletionProtection(d *schema.ResourceData, kind string) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf(
+			"%s %s is protected against deletion, set deletion_protection to false and apply before destroying it",
+			kind,
+			d.Id(),
+		)
+	}
+
+	return nil
+}
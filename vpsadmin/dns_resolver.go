@@ -3,27 +3,103 @@ package vpsadmin
 import (
 	"fmt"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"strings"
 )
 
-func getDnsResolverIdByLabel(api *client.Client, label string) (int64, error) {
+func dnsResolverList(api *client.Client) ([]*client.ActionDnsResolverIndexOutput, error) {
 	list := api.DnsResolver.Index.Prepare()
 	list.SetInput(&client.ActionDnsResolverIndexInput{
 		Limit: apiPageLimit,
 	})
 	list.Input.SelectParameters("Limit")
+	list.SetMetaInput(&client.ActionDnsResolverIndexMetaGlobalInput{
+		Includes: "location",
+	})
+	list.MetaInput.SelectParameters("Includes")
 	resp, err := list.Call()
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	} else if !resp.Status {
-		return 0, fmt.Errorf("Failed to list DNS resolvers: %s", resp.Message)
+		return nil, fmt.Errorf("Failed to list DNS resolvers: %s", resp.Message)
 	}
 
-	for _, resolver := range resp.Output {
+	return resp.Output, nil
+}
+
+func findDnsResolverByLabel(resolvers []*client.ActionDnsResolverIndexOutput, label string) (*client.ActionDnsResolverIndexOutput, error) {
+	for _, resolver := range resolvers {
 		if resolver.Label == label {
-			return resolver.Id, nil
+			return resolver, nil
+		}
+	}
+
+	labels := make([]string, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		labels = append(labels, resolver.Label)
+	}
+
+	return nil, fmt.Errorf(
+		"DNS resolver with label '%s' not found, available resolvers: %s",
+		label,
+		strings.Join(labels, ", "),
+	)
+}
+
+// isDnsResolverAvailableIn returns true if the resolver can be used by VPS
+// in location with the given label
+func isDnsResolverAvailableIn(resolver *client.ActionDnsResolverIndexOutput, location string) bool {
+	return resolver.IsUniversal || (resolver.Location != nil && resolver.Location.Label == location)
+}
+
+// validateDnsResolver checks that resolver with label exists and can be used
+// in location with the given label
+func validateDnsResolver(api *client.Client, label, location string) error {
+	resolvers, err := dnsResolverList(api)
+	if err != nil {
+		return err
+	}
+
+	resolver, err := findDnsResolverByLabel(resolvers, label)
+	if err != nil {
+		return err
+	}
+
+	if !isDnsResolverAvailableIn(resolver, location) {
+		return fmt.Errorf(
+			"DNS resolver '%s' is not available in location '%s'",
+			label,
+			location,
+		)
+	}
+
+	return nil
+}
+
+func getDnsResolverIdByLabel(api *client.Client, label string) (int64, error) {
+	resolvers, err := dnsResolverList(api)
+	if err != nil {
+		return 0, err
+	}
+
+	resolver, err := findDnsResolverByLabel(resolvers, label)
+	if err != nil {
+		return 0, err
+	}
+
+	return resolver.Id, nil
+}
+
+// splitDnsResolverAddresses splits addresses of a resolver, vpsAdmin stores
+// them in one string separated by commas
+func splitDnsResolverAddresses(addrs string) []interface{} {
+	ret := make([]interface{}, 0)
+
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			ret = append(ret, addr)
 		}
 	}
 
-	return 0, fmt.Errorf("DNS resolver with label '%s' not found", label)
+	return ret
 }
@@ -0,0 +1,96 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func testDnsResolvers(t *testing.T) *Config {
+	return newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/dns_resolvers" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "dns_resolver[limit]", strconv.Itoa(apiPageLimit))
+		writeAPIResponse(t, w, "dns_resolvers", []*client.ActionDnsResolverIndexOutput{
+			{Id: 1, Label: "Universal", IpAddr: "192.0.2.53", IpVersion: 4, IsUniversal: true},
+			{
+				Id:        2,
+				Label:     "Prague IPv6",
+				IpAddr:    "2001:db8::53, 2001:db8::54",
+				IpVersion: 6,
+				Location:  &client.ActionLocationShowOutput{Label: "Prague"},
+			},
+			{
+				Id:        3,
+				Label:     "Brno",
+				IpAddr:    "198.51.100.53",
+				IpVersion: 4,
+				Location:  &client.ActionLocationShowOutput{Label: "Brno"},
+			},
+		})
+	})
+}
+
+func TestDataSourceDnsResolversFiltersByLocation(t *testing.T) {
+	cfg := testDnsResolvers(t)
+
+	d := schema.TestResourceDataRaw(t, dataSourceDnsResolvers().Schema, map[string]interface{}{
+		"location": "Prague",
+	})
+
+	if err := dataSourceDnsResolversRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "resolvers.#", 2)
+	assertResourceValue(t, d, "resolvers.0.label", "Universal")
+	assertResourceValue(t, d, "resolvers.1.label", "Prague IPv6")
+	assertResourceValue(t, d, "resolvers.1.location", "Prague")
+	assertResourceValue(t, d, "resolvers.1.addresses.#", 2)
+	assertResourceValue(t, d, "resolvers.1.addresses.1", "2001:db8::54")
+}
+
+func TestDataSourceDnsResolversFiltersByVersionAndUniversal(t *testing.T) {
+	cfg := testDnsResolvers(t)
+
+	d := schema.TestResourceDataRaw(t, dataSourceDnsResolvers().Schema, map[string]interface{}{
+		"version":   4,
+		"universal": false,
+	})
+
+	if err := dataSourceDnsResolversRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "resolvers.#", 1)
+	assertResourceValue(t, d, "resolvers.0.id", 3)
+}
+
+func TestValidateDnsResolver(t *testing.T) {
+	api := testDnsResolvers(t).getClient()
+
+	if err := validateDnsResolver(api, "Universal", "Brno"); err != nil {
+		t.Fatalf("universal resolver: %v", err)
+	}
+
+	if err := validateDnsResolver(api, "Brno", "Brno"); err != nil {
+		t.Fatalf("location resolver: %v", err)
+	}
+
+	err := validateDnsResolver(api, "Brno", "Prague")
+	if err == nil || !strings.Contains(err.Error(), "not available in location 'Prague'") {
+		t.Fatalf("err = %v, want location error", err)
+	}
+
+	err = validateDnsResolver(api, "Bmo", "Brno")
+	if err == nil || !strings.Contains(err.Error(), "available resolvers: Universal, Prague IPv6, Brno") {
+		t.Fatalf("err = %v, want not found error", err)
+	}
+}
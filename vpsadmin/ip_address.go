@@ -1,23 +1,43 @@
 package vpsadmin
 
 import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
 	"log"
+	"net"
+	"sort"
 )
 
-func getPrimaryPublicHostIpv4Address(api *client.Client, vpsId int64) string {
-	return getPrimaryHostIpAddress(api, vpsId, 4, "public_access")
+type vpsHostIpAddresses struct {
+	publicIpv4  []*client.ActionHostIpAddressIndexOutput
+	privateIpv4 []*client.ActionHostIpAddressIndexOutput
+	publicIpv6  []*client.ActionHostIpAddressIndexOutput
 }
 
-func getPrimaryPrivateHostIpv4Address(api *client.Client, vpsId int64) string {
-	return getPrimaryHostIpAddress(api, vpsId, 4, "private_access")
-}
+func getVpsHostIpAddresses(api *client.Client, vpsId int64) (*vpsHostIpAddresses, error) {
+	var err error
+	ret := &vpsHostIpAddresses{}
+
+	ret.publicIpv4, err = vpsHostIpAddressList(api, vpsId, 4, "public_access")
+	if err != nil {
+		return nil, err
+	}
+
+	ret.privateIpv4, err = vpsHostIpAddressList(api, vpsId, 4, "private_access")
+	if err != nil {
+		return nil, err
+	}
+
+	ret.publicIpv6, err = vpsHostIpAddressList(api, vpsId, 6, "public_access")
+	if err != nil {
+		return nil, err
+	}
 
-func getPrimaryPublicHostIpv6Address(api *client.Client, vpsId int64) string {
-	return getPrimaryHostIpAddress(api, vpsId, 6, "public_access")
+	return ret, nil
 }
 
-func getPrimaryHostIpAddress(api *client.Client, vpsId int64, ipVersion int, role string) string {
+func vpsHostIpAddressList(api *client.Client, vpsId int64, ipVersion int, role string) ([]*client.ActionHostIpAddressIndexOutput, error) {
 	action := api.HostIpAddress.Index.Prepare()
 
 	input := action.NewInput()
@@ -25,31 +45,651 @@ func getPrimaryHostIpAddress(api *client.Client, vpsId int64, ipVersion int, rol
 	input.SetVersion(int64(ipVersion))
 	input.SetRole(role)
 	input.SetAssigned(true)
-	input.SetLimit(1)
+	input.SetLimit(apiPageLimit)
+
+	action.SetMetaInput(&client.ActionHostIpAddressIndexMetaGlobalInput{
+		Includes: "ip_address__network,ip_address__network_interface,ip_address__route_via",
+	})
+	action.MetaInput.SelectParameters("Includes")
 
 	log.Printf("[DEBUG] Listing host IP addresses: %+v", input)
 
 	resp, err := action.Call()
 
 	if err != nil {
-		log.Printf("[INFO] Failed to list host IP addresses: %v", err)
-		return ""
+		return nil, fmt.Errorf("Failed to list host IP addresses: %v", err)
 	} else if !resp.Status {
-		log.Printf("[INFO] Failed to list host IP addresses: %s", resp.Message)
+		return nil, fmt.Errorf("Failed to list host IP addresses: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func (h *vpsHostIpAddresses) all() []*client.ActionHostIpAddressIndexOutput {
+	ret := make([]*client.ActionHostIpAddressIndexOutput, 0, len(h.publicIpv4)+len(h.privateIpv4)+len(h.publicIpv6))
+	ret = append(ret, h.publicIpv4...)
+	ret = append(ret, h.privateIpv4...)
+	ret = append(ret, h.publicIpv6...)
+	return ret
+}
+
+func primaryHostIpAddress(addrs []*client.ActionHostIpAddressIndexOutput) string {
+	if len(addrs) == 0 {
 		return ""
+	}
+
+	return addrs[0].Addr
+}
+
+func ipAddressListSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"address": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Host IP address",
+					Computed:    true,
+				},
+				"prefix": &schema.Schema{
+					Type:        schema.TypeInt,
+					Description: "Prefix of the routed address",
+					Computed:    true,
+				},
+				"network": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Network the address belongs to, in CIDR notation",
+					Computed:    true,
+				},
+				"interface": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Name of the network interface the address is assigned to",
+					Computed:    true,
+				},
+				"reverse_record": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Reverse DNS (PTR) record of the address",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func flattenHostIpAddresses(addrs []*client.ActionHostIpAddressIndexOutput) []interface{} {
+	ret := make([]interface{}, 0, len(addrs))
+
+	for _, addr := range addrs {
+		v := map[string]interface{}{
+			"address":        addr.Addr,
+			"prefix":         0,
+			"network":        "",
+			"interface":      "",
+			"reverse_record": addr.ReverseRecordValue,
+		}
+
+		if ip := addr.IpAddress; ip != nil {
+			v["prefix"] = int(ip.Prefix)
+
+			if ip.Network != nil {
+				v["network"] = fmt.Sprintf("%s/%d", ip.Network.Address, ip.Network.Prefix)
+			}
+
+			if ip.NetworkInterface != nil {
+				v["interface"] = ip.NetworkInterface.Name
+			}
+		}
+
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+func hostIpAddressShow(api *client.Client, id int64) (*client.ActionHostIpAddressShowOutput, error) {
+	show := api.HostIpAddress.Show.Prepare()
+	show.SetPathParamInt("host_ip_address_id", id)
+	show.SetMetaInput(&client.ActionHostIpAddressShowMetaGlobalInput{
+		Includes: "ip_address__network,ip_address__network_interface__vps",
+	})
+	show.MetaInput.SelectParameters("Includes")
+
+	resp, err := show.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Host IP address show failed: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func findHostIpAddressByAddr(api *client.Client, addr string) (*client.ActionHostIpAddressIndexOutput, error) {
+	addr = canonicalIpAddress(addr)
+	action := api.HostIpAddress.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetAddr(addr)
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list host IP addresses: %s", resp.Message)
+	}
+
+	var found *client.ActionHostIpAddressIndexOutput
+
+	for _, hostAddr := range resp.Output {
+		if canonicalIpAddress(hostAddr.Addr) != addr {
+			continue
+		} else if found != nil {
+			return nil, fmt.Errorf(
+				"Host IP address '%s' is ambiguous, found IDs %d and %d",
+				addr, found.Id, hostAddr.Id,
+			)
+		}
+
+		found = hostAddr
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("Host IP address '%s' not found", addr)
+	}
+
+	return found, nil
+}
+
+func setHostIpReverseRecord(api *client.Client, id int64, value string) error {
+	update := api.HostIpAddress.Update.Prepare()
+	update.SetPathParamInt("host_ip_address_id", id)
+
+	input := update.NewInput()
+	input.SetReverseRecordValue(value)
+
+	log.Printf("[INFO] Setting reverse record of host IP address %d to '%s'", id, value)
+
+	resp, err := update.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Reverse record update failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Reverse record update failed: %v", err)
+	}
+
+	return nil
+}
+
+// suppressEquivalentIpAddress suppresses diffs between different notations
+// of the same address, e.g. IPv6 addresses with and without leading zeros
+func suppressEquivalentIpAddress(k, old, new string, d *schema.ResourceData) bool {
+	oldIp := net.ParseIP(old)
+	newIp := net.ParseIP(new)
+
+	return oldIp != nil && newIp != nil && oldIp.Equal(newIp)
+}
+
+// canonicalIpAddress returns addr in the notation used by vpsAdmin, so that it
+// can be used in API filters
+func canonicalIpAddress(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+
+	return addr
+}
+
+// checkHostIpAddressInRoutedAddress returns an error if addr is not a part of
+// the routed address ip
+func checkHostIpAddressInRoutedAddress(ip *client.ActionIpAddressShowOutput, addr string) error {
+	_, routed, err := net.ParseCIDR(fmt.Sprintf("%s/%d", ip.Addr, ip.Prefix))
+	if err != nil {
+		return fmt.Errorf("Invalid routed address %s/%d: %v", ip.Addr, ip.Prefix, err)
+	}
+
+	hostIp := net.ParseIP(addr)
+	if hostIp == nil {
+		return fmt.Errorf("Invalid host IP address '%s'", addr)
+	}
+
+	if !routed.Contains(hostIp) {
+		return fmt.Errorf("Host IP address %s is not within routed address %s", addr, routed)
+	}
+
+	return nil
+}
+
+func findRoutedHostIpAddress(api *client.Client, ipId int64, addr string) (*client.ActionHostIpAddressIndexOutput, error) {
+	action := api.HostIpAddress.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetIpAddress(ipId)
+	input.SetAddr(canonicalIpAddress(addr))
+	input.SetLimit(1)
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list host IP addresses: %s", resp.Message)
 	} else if len(resp.Output) == 0 {
-		return ""
+		return nil, nil
 	}
 
-	return resp.Output[0].Addr
+	return resp.Output[0], nil
 }
 
-func getPrimaryConnectionAddress(publicIpv4, privateIpv4, publicIpv6 string) string {
-	if publicIpv4 != "" {
-		return publicIpv4
-	} else if publicIpv6 != "" {
-		return publicIpv6
+func createHostIpAddress(api *client.Client, ipId int64, addr string) (*client.ActionHostIpAddressCreateOutput, error) {
+	create := api.HostIpAddress.Create.Prepare()
+
+	input := create.NewInput()
+	input.SetIpAddress(ipId)
+	input.SetAddr(addr)
+
+	log.Printf("[INFO] Creating host IP address %s in IP address %d", addr, ipId)
+
+	resp, err := create.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Host IP address creation failed: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func assignHostIpAddress(api *client.Client, id int64) error {
+	assign := api.HostIpAddress.Assign.Prepare()
+	assign.SetPathParamInt("host_ip_address_id", id)
+
+	log.Printf("[INFO] Assigning host IP address %d", id)
+
+	resp, err := assign.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Host IP address assignment failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Host IP address assignment failed: %v", err)
+	}
+
+	return nil
+}
+
+func freeHostIpAddress(api *client.Client, id int64) error {
+	free := api.HostIpAddress.Free.Prepare()
+	free.SetPathParamInt("host_ip_address_id", id)
+
+	log.Printf("[INFO] Freeing host IP address %d", id)
+
+	resp, err := free.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Host IP address removal failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Host IP address removal failed: %v", err)
+	}
+
+	return nil
+}
+
+func deleteHostIpAddress(api *client.Client, id int64) error {
+	del := api.HostIpAddress.Delete.Prepare()
+	del.SetPathParamInt("host_ip_address_id", id)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Host IP address deletion failed: %s", resp.Message)
+	}
+
+	return nil
+}
+
+var defaultConnectionAddressPreference = []string{"public_ipv4", "public_ipv6", "private_ipv4"}
+
+// getPreferredConnectionAddress returns the first available address in the
+// order given by preference
+func getPreferredConnectionAddress(preference []string, publicIpv4, privateIpv4, publicIpv6 string) string {
+	addrs := map[string]string{
+		"public_ipv4":  publicIpv4,
+		"private_ipv4": privateIpv4,
+		"public_ipv6":  publicIpv6,
+	}
+
+	for _, kind := range preference {
+		if addr := addrs[kind]; addr != "" {
+			return addr
+		}
+	}
+
+	return ""
+}
+
+func vpsIpAddressList(api *client.Client, vpsId int64, ipVersion int, role string) ([]*client.ActionIpAddressIndexOutput, error) {
+	var ret []*client.ActionIpAddressIndexOutput
+
+	for offset := int64(0); ; offset += apiPageLimit {
+		action := api.IpAddress.Index.Prepare()
+
+		input := action.NewInput()
+		input.SetVps(vpsId)
+		input.SetVersion(int64(ipVersion))
+		input.SetRole(role)
+		input.SetOffset(offset)
+		input.SetLimit(apiPageLimit)
+
+		resp, err := action.Call()
+
+		if err != nil {
+			return nil, err
+		} else if !resp.Status {
+			return nil, fmt.Errorf("Failed to list IP addresses: %s", resp.Message)
+		}
+
+		ret = append(ret, resp.Output...)
+
+		if len(resp.Output) < apiPageLimit {
+			return ret, nil
+		}
+	}
+}
+
+func ipAddressShow(api *client.Client, id int64) (*client.ActionIpAddressShowOutput, error) {
+	show := api.IpAddress.Show.Prepare()
+	show.SetPathParamInt("ip_address_id", id)
+	show.SetMetaInput(&client.ActionIpAddressShowMetaGlobalInput{
+		Includes: "network,network_interface__vps",
+	})
+	show.MetaInput.SelectParameters("Includes")
+
+	resp, err := show.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("IP address show failed: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func findIpAddressByAddr(api *client.Client, addr string) (*client.ActionIpAddressIndexOutput, error) {
+	action := api.IpAddress.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetAddr(canonicalIpAddress(addr))
+	input.SetLimit(1)
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list IP addresses: %s", resp.Message)
+	} else if len(resp.Output) == 0 {
+		return nil, fmt.Errorf("IP address '%s' not found", addr)
+	}
+
+	return resp.Output[0], nil
+}
+
+// findFreeIpAddress returns an address which is neither assigned to any
+// interface nor owned by any user, so that automatically picked addresses
+// never take someone's reservation
+func findFreeIpAddress(api *client.Client, locationId int64, ipVersion int, role string) (*client.ActionIpAddressIndexOutput, error) {
+	action := api.IpAddress.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetLocation(locationId)
+	input.SetVersion(int64(ipVersion))
+	input.SetRole(role)
+	input.SetAssignedToInterface(false)
+	input.SetUserNil(true)
+	input.SetLimit(apiPageLimit)
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list free IP addresses: %s", resp.Message)
+	}
+
+	for _, ip := range resp.Output {
+		if ip.User == nil {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No free IPv%d %s address available", ipVersion, role)
+}
+
+// setIpAddressOwner makes the IP address owned by user with userId, or by
+// nobody if userId is 0
+func setIpAddressOwner(api *client.Client, ipId int64, userId int64) error {
+	update := api.IpAddress.Update.Prepare()
+	update.SetPathParamInt("ip_address_id", ipId)
+
+	input := update.NewInput()
+
+	if userId == 0 {
+		input.SetUserNil(true)
 	} else {
-		return privateIpv4
+		input.SetUser(userId)
 	}
+
+	log.Printf("[INFO] Setting owner of IP address %d to user %d", ipId, userId)
+
+	resp, err := update.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("IP address ownership change failed: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// reserveIpAddress makes the IP address owned by the current user
+func reserveIpAddress(api *client.Client, ipId int64) error {
+	user, err := getCurrentUser(api)
+	if err != nil {
+		return err
+	}
+
+	return setIpAddressOwner(api, ipId, user.Id)
+}
+
+// pickIpAddress finds the IP address requested by attributes address,
+// version, role and location of an IP address resource
+func pickIpAddress(api *client.Client, d *schema.ResourceData, defaultLocationId int64) (*client.ActionIpAddressIndexOutput, error) {
+	if v, ok := d.GetOk("address"); ok {
+		return findIpAddressByAddr(api, v.(string))
+	}
+
+	locationId := defaultLocationId
+
+	if v, ok := d.GetOk("location"); ok {
+		var err error
+
+		locationId, err = getLocationIdByLabel(api, v.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	version := 4
+	if v, ok := d.GetOk("version"); ok {
+		version = v.(int)
+	}
+
+	role := "public_access"
+	if v, ok := d.GetOk("role"); ok {
+		role = v.(string)
+	}
+
+	return findFreeIpAddress(api, locationId, version, role)
+}
+
+func freeIpAddressList(api *client.Client, filter networkFilter, limit int) ([]*client.ActionIpAddressIndexOutput, error) {
+	action := api.IpAddress.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetAssignedToInterface(false)
+	input.SetLimit(int64(limit))
+
+	if filter.locationId != 0 {
+		input.SetLocation(filter.locationId)
+	}
+
+	if filter.ipVersion != 0 {
+		input.SetVersion(int64(filter.ipVersion))
+	}
+
+	if filter.role != "" {
+		input.SetRole(filter.role)
+	}
+
+	if filter.purpose != "" {
+		input.SetPurpose(filter.purpose)
+	}
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list free IP addresses: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func assignIpAddress(api *client.Client, ipId int64, netifId int64) error {
+	assign := api.IpAddress.AssignWithHostAddress.Prepare()
+	assign.SetPathParamInt("ip_address_id", ipId)
+
+	input := assign.NewInput()
+	input.SetNetworkInterface(netifId)
+
+	log.Printf("[INFO] Assigning IP address %d to network interface %d", ipId, netifId)
+
+	resp, err := assign.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("IP address assignment failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("IP address assignment failed: %v", err)
+	}
+
+	return nil
+}
+
+func freeIpAddress(api *client.Client, ipId int64) error {
+	free := api.IpAddress.Free.Prepare()
+	free.SetPathParamInt("ip_address_id", ipId)
+
+	log.Printf("[INFO] Freeing IP address %d", ipId)
+
+	resp, err := free.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("IP address removal failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("IP address removal failed: %v", err)
+	}
+
+	return nil
+}
+
+// freeAssignedIpAddress frees the IP address only if it is still assigned to
+// a network interface
+func freeAssignedIpAddress(api *client.Client, ipId int64) error {
+	ip, err := ipAddressShow(api, ipId)
+	if err != nil {
+		return err
+	}
+
+	if ip.NetworkInterface == nil {
+		log.Printf("[INFO] IP address %s is not assigned, not freeing", ip.Addr)
+		return nil
+	}
+
+	return freeIpAddress(api, ipId)
+}
+
+// reconcileVpsIpAddressCount adds or frees IP addresses of the given version
+// and role, so that the VPS ends up with exactly count of them
+func reconcileVpsIpAddressCount(api *client.Client, vps *client.ActionVpsShowOutput, ipVersion int, role string, count int) error {
+	addrs, err := vpsIpAddressList(api, vps.Id, ipVersion, role)
+	if err != nil {
+		return err
+	}
+
+	if len(addrs) > count {
+		// Free the most recently added addresses first, so that the oldest
+		// address, which is the primary one, is kept regardless of API order
+		sort.Slice(addrs, func(i, j int) bool {
+			return addrs[i].Id < addrs[j].Id
+		})
+
+		for i := len(addrs) - 1; i >= count; i-- {
+			if err := freeIpAddress(api, addrs[i].Id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if len(addrs) == count {
+		return nil
+	}
+
+	netif, err := getVpsNetworkInterface(api, vps.Id, 0, "")
+	if err != nil {
+		return err
+	}
+
+	for i := len(addrs); i < count; i++ {
+		ip, err := findFreeIpAddress(api, vps.Node.Location.Id, ipVersion, role)
+		if err != nil {
+			return err
+		}
+
+		if err := assignIpAddress(api, ip.Id, netif.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -1,8 +1,14 @@
 package vpsadmin
 
-import "testing"
+import (
+	"net/http"
+	"strings"
+	"testing"
 
-func TestGetPrimaryConnectionAddress(t *testing.T) {
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestGetPreferredConnectionAddressDefaultOrder(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -41,14 +47,233 @@ func TestGetPrimaryConnectionAddress(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := getPrimaryConnectionAddress(
+			got := getPreferredConnectionAddress(
+				defaultConnectionAddressPreference,
 				tt.publicIpv4,
 				tt.privateIpv4,
 				tt.publicIpv6,
 			)
 			if got != tt.want {
-				t.Fatalf("getPrimaryConnectionAddress() = %q, want %q", got, tt.want)
+				t.Fatalf("getPreferredConnectionAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPreferredConnectionAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		preference []string
+		want       string
+	}{
+		{
+			name:       "IPv6 first",
+			preference: []string{"public_ipv6", "public_ipv4"},
+			want:       "2001:db8::10",
+		},
+		{
+			name:       "private network only",
+			preference: []string{"private_ipv4"},
+			want:       "10.0.0.10",
+		},
+		{
+			name:       "skips unavailable kinds",
+			preference: []string{"public_ipv4", "private_ipv4"},
+			want:       "10.0.0.10",
+		},
+		{
+			name: "empty preference",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := getPreferredConnectionAddress(tt.preference, "", "10.0.0.10", "2001:db8::10")
+			if got != tt.want {
+				t.Fatalf("getPreferredConnectionAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileVpsIpAddressCountFreesExtraAddresses(t *testing.T) {
+	var freed []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/ip_addresses":
+			assertQueryValue(t, r, "ip_address[vps]", "123")
+			assertQueryValue(t, r, "ip_address[version]", "4")
+			assertQueryValue(t, r, "ip_address[role]", "public_access")
+			writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+				{Id: 3, Addr: "198.51.100.12"},
+				{Id: 1, Addr: "198.51.100.10"},
+				{Id: 2, Addr: "198.51.100.11"},
+			})
+		case "/v7.0/ip_addresses/2/free", "/v7.0/ip_addresses/3/free":
+			freed = append(freed, r.URL.Path)
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressFreeOutput{})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	vps := &client.ActionVpsShowOutput{Id: 123, Node: testNode("node-a", "prg")}
+
+	if err := reconcileVpsIpAddressCount(cfg.getClient(), vps, 4, "public_access", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/v7.0/ip_addresses/3/free", "/v7.0/ip_addresses/2/free"}
+	if len(freed) != len(want) || freed[0] != want[0] || freed[1] != want[1] {
+		t.Fatalf("freed = %v, want %v", freed, want)
+	}
+}
+
+func TestVpsIpAddressListFetchesAllPages(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/ip_addresses" {
+			http.NotFound(w, r)
+			return
+		}
+
+		addrs := []*client.ActionIpAddressIndexOutput{{Id: apiPageLimit + 1}}
+
+		if r.URL.Query().Get("ip_address[offset]") == "0" {
+			addrs = make([]*client.ActionIpAddressIndexOutput, apiPageLimit)
+			for i := range addrs {
+				addrs[i] = &client.ActionIpAddressIndexOutput{Id: int64(i + 1)}
 			}
+		}
+
+		writeAPIResponse(t, w, "ip_addresses", addrs)
+	})
+
+	addrs, err := vpsIpAddressList(cfg.getClient(), 123, 4, "public_access")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(addrs) != apiPageLimit+1 {
+		t.Fatalf("len = %d, want %d", len(addrs), apiPageLimit+1)
+	}
+}
+
+func TestReconcileVpsIpAddressCountAssignsMissingAddresses(t *testing.T) {
+	var assigned int
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/ip_addresses":
+			if r.URL.Query().Get("ip_address[vps]") == "123" {
+				writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{})
+				return
+			}
+
+			assertQueryValue(t, r, "ip_address[assigned_to_interface]", "0")
+			writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+				{Id: 9, Addr: "2001:db8:1::"},
+			})
+		case "/v7.0/network_interfaces":
+			assertQueryValue(t, r, "network_interface[vps]", "123")
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 55, Name: "venet0"},
+			})
+		case "/v7.0/ip_addresses/9/assign_with_host_address":
+			if body := readRequestBody(t, r); !strings.Contains(body, `"network_interface":55`) {
+				t.Errorf("assign body = %s", body)
+			}
+			assigned++
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressAssignWithHostAddressOutput{})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	vps := &client.ActionVpsShowOutput{Id: 123, Node: testNode("node-a", "prg")}
+
+	if err := reconcileVpsIpAddressCount(cfg.getClient(), vps, 6, "public_access", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if assigned != 2 {
+		t.Fatalf("assigned = %d, want 2", assigned)
+	}
+}
+
+func TestFindHostIpAddressByAddrMatchesExactAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  []*client.ActionHostIpAddressIndexOutput
+		wantId  int64
+		wantErr string
+	}{
+		{
+			name: "skips partial matches",
+			output: []*client.ActionHostIpAddressIndexOutput{
+				{Id: 41, Addr: "198.51.100.100"},
+				{Id: 42, Addr: "198.51.100.10"},
+			},
+			wantId: 42,
+		},
+		{
+			name:    "not found",
+			output:  []*client.ActionHostIpAddressIndexOutput{{Id: 41, Addr: "198.51.100.100"}},
+			wantErr: "not found",
+		},
+		{
+			name: "ambiguous",
+			output: []*client.ActionHostIpAddressIndexOutput{
+				{Id: 42, Addr: "198.51.100.10"},
+				{Id: 43, Addr: "198.51.100.10"},
+			},
+			wantErr: "ambiguous",
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			assertQueryValue(t, r, "host_ip_address[addr]", "198.51.100.10")
+			writeAPIResponse(t, w, "host_ip_addresses", tt.output)
+		})
+
+		got, err := findHostIpAddressByAddr(cfg.getClient(), "198.51.100.10")
+
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if got.Id != tt.wantId {
+			t.Fatalf("%s: id = %d, want %d", tt.name, got.Id, tt.wantId)
+		}
+	}
+}
+
+func TestFindIpAddressByAddrUsesCanonicalAddress(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		assertQueryValue(t, r, "ip_address[addr]", "2001:db8:1::25")
+		writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+			{Id: 9, Addr: "2001:db8:1::25"},
 		})
+	})
+
+	ip, err := findIpAddressByAddr(cfg.getClient(), "2001:0db8:0001:0000::0025")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ip.Id != 9 {
+		t.Fatalf("id = %d, want 9", ip.Id)
 	}
 }
@@ -0,0 +1,229 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"sort"
+)
+
+type ipTrafficFilter struct {
+	vpsId    int64
+	ipId     int64
+	protocol string
+	dateFrom string
+	dateTo   string
+}
+
+type ipTrafficCounters struct {
+	packetsIn  int64
+	packetsOut int64
+	bytesIn    int64
+	bytesOut   int64
+}
+
+// ipTrafficSumProtocol is the protocol of records that vpsAdmin has already
+// summed over all protocols. These are skipped, so that traffic is not
+// counted twice.
+const ipTrafficSumProtocol = "sum"
+
+func (c *ipTrafficCounters) add(packetsIn, packetsOut, bytesIn, bytesOut int64) {
+	c.packetsIn += packetsIn
+	c.packetsOut += packetsOut
+	c.bytesIn += bytesIn
+	c.bytesOut += bytesOut
+}
+
+func (c *ipTrafficCounters) flatten() map[string]interface{} {
+	return map[string]interface{}{
+		"packets_in":  int(c.packetsIn),
+		"packets_out": int(c.packetsOut),
+		"bytes_in":    int(c.bytesIn),
+		"bytes_out":   int(c.bytesOut),
+	}
+}
+
+// ipTrafficList fetches all traffic records matching the filter, page by page
+func ipTrafficList(api *client.Client, filter ipTrafficFilter) ([]*client.ActionIpTrafficIndexOutput, error) {
+	var ret []*client.ActionIpTrafficIndexOutput
+
+	for offset := int64(0); ; offset += apiPageLimit {
+		action := api.IpTraffic.Index.Prepare()
+
+		input := action.NewInput()
+		input.SetOffset(offset)
+		input.SetLimit(apiPageLimit)
+
+		if filter.vpsId != 0 {
+			input.SetVps(filter.vpsId)
+		}
+
+		if filter.ipId != 0 {
+			input.SetIpAddress(filter.ipId)
+		}
+
+		if filter.protocol != "" {
+			input.SetProtocol(filter.protocol)
+		}
+
+		if filter.dateFrom != "" {
+			input.SetDateFrom(filter.dateFrom)
+		}
+
+		if filter.dateTo != "" {
+			input.SetDateTo(filter.dateTo)
+		}
+
+		log.Printf("[DEBUG] Listing IP traffic: %+v", input)
+
+		resp, err := action.Call()
+
+		if err != nil {
+			return nil, err
+		} else if !resp.Status {
+			return nil, fmt.Errorf("Failed to list IP traffic: %s", resp.Message)
+		}
+
+		ret = append(ret, resp.Output...)
+
+		if len(resp.Output) < apiPageLimit {
+			return ret, nil
+		}
+	}
+}
+
+// ipTrafficMonthlyList fetches all monthly traffic summaries matching
+// the filter, page by page
+func ipTrafficMonthlyList(api *client.Client, filter ipTrafficFilter) ([]*client.ActionIpTrafficMonthlySummaryIndexOutput, error) {
+	var ret []*client.ActionIpTrafficMonthlySummaryIndexOutput
+
+	for offset := int64(0); ; offset += apiPageLimit {
+		action := api.IpTrafficMonthlySummary.Index.Prepare()
+
+		input := action.NewInput()
+		input.SetOffset(offset)
+		input.SetLimit(apiPageLimit)
+
+		if filter.vpsId != 0 {
+			input.SetVps(filter.vpsId)
+		}
+
+		if filter.ipId != 0 {
+			input.SetIpAddress(filter.ipId)
+		}
+
+		if filter.protocol != "" {
+			input.SetProtocol(filter.protocol)
+		}
+
+		if filter.dateFrom != "" {
+			input.SetDateFrom(filter.dateFrom)
+		}
+
+		if filter.dateTo != "" {
+			input.SetDateTo(filter.dateTo)
+		}
+
+		log.Printf("[DEBUG] Listing monthly IP traffic: %+v", input)
+
+		resp, err := action.Call()
+
+		if err != nil {
+			return nil, err
+		} else if !resp.Status {
+			return nil, fmt.Errorf("Failed to list monthly IP traffic: %s", resp.Message)
+		}
+
+		ret = append(ret, resp.Output...)
+
+		if len(resp.Output) < apiPageLimit {
+			return ret, nil
+		}
+	}
+}
+
+// aggregateIpTraffic sums traffic records in total and per protocol
+func aggregateIpTraffic(records []*client.ActionIpTrafficIndexOutput) (*ipTrafficCounters, []interface{}) {
+	total := &ipTrafficCounters{}
+	byProtocol := make(map[string]*ipTrafficCounters)
+
+	for _, t := range records {
+		if t.Protocol == ipTrafficSumProtocol {
+			continue
+		}
+
+		total.add(t.PacketsIn, t.PacketsOut, t.BytesIn, t.BytesOut)
+
+		c, ok := byProtocol[t.Protocol]
+		if !ok {
+			c = &ipTrafficCounters{}
+			byProtocol[t.Protocol] = c
+		}
+
+		c.add(t.PacketsIn, t.PacketsOut, t.BytesIn, t.BytesOut)
+	}
+
+	protocols := make([]string, 0, len(byProtocol))
+	for p := range byProtocol {
+		protocols = append(protocols, p)
+	}
+	sort.Strings(protocols)
+
+	ret := make([]interface{}, 0, len(protocols))
+	for _, p := range protocols {
+		m := byProtocol[p].flatten()
+		m["protocol"] = p
+		ret = append(ret, m)
+	}
+
+	return total, ret
+}
+
+// aggregateIpTrafficMonthly sums monthly traffic summaries of all addresses
+// and protocols by month, ordered from the oldest month
+func aggregateIpTrafficMonthly(records []*client.ActionIpTrafficMonthlySummaryIndexOutput) []interface{} {
+	type yearMonth struct {
+		year  int64
+		month int64
+	}
+
+	byMonth := make(map[yearMonth]*ipTrafficCounters)
+
+	for _, t := range records {
+		if t.Protocol == ipTrafficSumProtocol {
+			continue
+		}
+
+		key := yearMonth{year: t.Year, month: t.Month}
+
+		c, ok := byMonth[key]
+		if !ok {
+			c = &ipTrafficCounters{}
+			byMonth[key] = c
+		}
+
+		c.add(t.PacketsIn, t.PacketsOut, t.BytesIn, t.BytesOut)
+	}
+
+	months := make([]yearMonth, 0, len(byMonth))
+	for key := range byMonth {
+		months = append(months, key)
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if months[i].year != months[j].year {
+			return months[i].year < months[j].year
+		}
+
+		return months[i].month < months[j].month
+	})
+
+	ret := make([]interface{}, 0, len(months))
+	for _, key := range months {
+		m := byMonth[key].flatten()
+		m["year"] = int(key.year)
+		m["month"] = int(key.month)
+		ret = append(ret, m)
+	}
+
+	return ret
+}
@@ -0,0 +1,103 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestIpTrafficListFetchesAllPages(t *testing.T) {
+	var offsets []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/ip_traffics" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "ip_traffic[vps]", "123")
+		assertQueryValue(t, r, "ip_traffic[date_from]", "2026-01-01T00:00:00Z")
+		assertQueryValue(t, r, "ip_traffic[limit]", strconv.Itoa(apiPageLimit))
+
+		offset := r.URL.Query().Get("ip_traffic[offset]")
+		offsets = append(offsets, offset)
+
+		count := apiPageLimit
+		if offset != "0" {
+			count = 2
+		}
+
+		records := make([]*client.ActionIpTrafficIndexOutput, count)
+		for i := range records {
+			records[i] = &client.ActionIpTrafficIndexOutput{Protocol: "tcp", BytesIn: 1}
+		}
+
+		writeAPIResponse(t, w, "ip_traffics", records)
+	})
+
+	records, err := ipTrafficList(cfg.getClient(), ipTrafficFilter{
+		vpsId:    123,
+		dateFrom: "2026-01-01T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != apiPageLimit+2 {
+		t.Fatalf("records = %d, want %d", len(records), apiPageLimit+2)
+	}
+	if len(offsets) != 2 || offsets[1] != strconv.Itoa(apiPageLimit) {
+		t.Fatalf("offsets = %v", offsets)
+	}
+}
+
+func TestAggregateIpTraffic(t *testing.T) {
+	t.Parallel()
+
+	total, protocols := aggregateIpTraffic([]*client.ActionIpTrafficIndexOutput{
+		{Protocol: "udp", PacketsIn: 1, PacketsOut: 2, BytesIn: 100, BytesOut: 200},
+		{Protocol: "tcp", PacketsIn: 10, PacketsOut: 20, BytesIn: 1000, BytesOut: 2000},
+		{Protocol: "tcp", PacketsIn: 5, PacketsOut: 5, BytesIn: 500, BytesOut: 500},
+		{Protocol: "sum", PacketsIn: 16, PacketsOut: 27, BytesIn: 1600, BytesOut: 2700},
+	})
+
+	if total.packetsIn != 16 || total.packetsOut != 27 || total.bytesIn != 1600 || total.bytesOut != 2700 {
+		t.Fatalf("total = %+v", total)
+	}
+
+	if len(protocols) != 2 {
+		t.Fatalf("protocols = %v, want 2 entries", protocols)
+	}
+
+	tcp := protocols[0].(map[string]interface{})
+	if tcp["protocol"] != "tcp" || tcp["bytes_in"] != 1500 || tcp["packets_out"] != 25 {
+		t.Fatalf("protocols[0] = %v", tcp)
+	}
+}
+
+func TestAggregateIpTrafficMonthly(t *testing.T) {
+	t.Parallel()
+
+	months := aggregateIpTrafficMonthly([]*client.ActionIpTrafficMonthlySummaryIndexOutput{
+		{Year: 2026, Month: 2, Protocol: "tcp", BytesIn: 100, BytesOut: 10},
+		{Year: 2025, Month: 12, Protocol: "tcp", BytesIn: 1000, BytesOut: 1},
+		{Year: 2026, Month: 2, Protocol: "udp", BytesIn: 50, BytesOut: 5},
+		{Year: 2026, Month: 2, Protocol: "sum", BytesIn: 150, BytesOut: 15},
+	})
+
+	if len(months) != 2 {
+		t.Fatalf("months = %v, want 2 entries", months)
+	}
+
+	dec := months[0].(map[string]interface{})
+	if dec["year"] != 2025 || dec["month"] != 12 || dec["bytes_in"] != 1000 {
+		t.Fatalf("months[0] = %v", dec)
+	}
+
+	feb := months[1].(map[string]interface{})
+	if feb["year"] != 2026 || feb["month"] != 2 || feb["bytes_in"] != 150 || feb["bytes_out"] != 15 {
+		t.Fatalf("months[1] = %v", feb)
+	}
+}
@@ -0,0 +1,221 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// vpsAdmin numbers weekdays from 0 (Sunday) to 6 (Saturday)
+const maintenanceWindowDays = 7
+
+// Windows which vpsAdmin opens for new VPS, every day from 01:00 to 05:00
+const (
+	defaultMaintenanceWindowOpensAt  = 60
+	defaultMaintenanceWindowClosesAt = 5 * 60
+)
+
+func maintenanceWindowSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: "Weekly maintenance windows. Days which are not listed are closed for maintenance. Without any window, vpsAdmin defaults are used, i.e. every day from 01:00 to 05:00.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"weekday": &schema.Schema{
+					Type:         schema.TypeInt,
+					Description:  "Day of the week, 0 is Sunday and 6 is Saturday",
+					Required:     true,
+					ValidateFunc: validateMaintenanceWindowWeekday,
+				},
+				"opens_at": &schema.Schema{
+					Type:         schema.TypeString,
+					Description:  "Time when the window opens, in HH:MM format",
+					Required:     true,
+					ValidateFunc: validateMaintenanceWindowTime,
+				},
+				"closes_at": &schema.Schema{
+					Type:         schema.TypeString,
+					Description:  "Time when the window closes, in HH:MM format, 24:00 for midnight",
+					Required:     true,
+					ValidateFunc: validateMaintenanceWindowTime,
+				},
+			},
+		},
+	}
+}
+
+func vpsMaintenanceWindowList(api *client.Client, vpsId int64) ([]*client.ActionVpsMaintenanceWindowIndexOutput, error) {
+	list := api.Vps.MaintenanceWindow.Index.Prepare()
+	list.SetPathParamInt("vps_id", vpsId)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("VPS maintenance window list failed: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+func flattenMaintenanceWindows(windows []*client.ActionVpsMaintenanceWindowIndexOutput) []interface{} {
+	ret := make([]interface{}, 0, len(windows))
+
+	for _, w := range windows {
+		if !w.IsOpen {
+			continue
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"weekday":   int(w.Weekday),
+			"opens_at":  formatMaintenanceWindowTime(w.OpensAt),
+			"closes_at": formatMaintenanceWindowTime(w.ClosesAt),
+		})
+	}
+
+	return ret
+}
+
+// defaultMaintenanceWindows returns windows which are set when all windows
+// are removed from the configuration
+func defaultMaintenanceWindows() []interface{} {
+	ret := make([]interface{}, 0, maintenanceWindowDays)
+
+	for weekday := 0; weekday < maintenanceWindowDays; weekday++ {
+		ret = append(ret, map[string]interface{}{
+			"weekday":   weekday,
+			"opens_at":  formatMaintenanceWindowTime(defaultMaintenanceWindowOpensAt),
+			"closes_at": formatMaintenanceWindowTime(defaultMaintenanceWindowClosesAt),
+		})
+	}
+
+	return ret
+}
+
+func isDefaultMaintenanceWindows(windows []*client.ActionVpsMaintenanceWindowIndexOutput) bool {
+	open := 0
+
+	for _, w := range windows {
+		if !w.IsOpen {
+			return false
+		} else if w.OpensAt != defaultMaintenanceWindowOpensAt || w.ClosesAt != defaultMaintenanceWindowClosesAt {
+			return false
+		}
+
+		open++
+	}
+
+	return open == maintenanceWindowDays
+}
+
+func setVpsMaintenanceWindows(api *client.Client, vpsId int64, windows []interface{}) error {
+	days := make([]map[string]interface{}, maintenanceWindowDays)
+
+	for _, v := range windows {
+		w := v.(map[string]interface{})
+		weekday := w["weekday"].(int)
+
+		if days[weekday] != nil {
+			return fmt.Errorf("Maintenance window for weekday %d is defined more than once", weekday)
+		}
+
+		days[weekday] = w
+	}
+
+	for weekday, w := range days {
+		update := api.Vps.MaintenanceWindow.Update.Prepare()
+		update.SetPathParamInt("vps_id", vpsId)
+		update.SetPathParamInt("maintenance_window_id", int64(weekday))
+
+		input := update.NewInput()
+
+		if w == nil {
+			input.SetIsOpen(false)
+		} else {
+			opensAt, err := parseMaintenanceWindowTime(w["opens_at"].(string))
+			if err != nil {
+				return err
+			}
+
+			closesAt, err := parseMaintenanceWindowTime(w["closes_at"].(string))
+			if err != nil {
+				return err
+			}
+
+			if opensAt >= closesAt {
+				return fmt.Errorf(
+					"Maintenance window for weekday %d must open before it closes",
+					weekday,
+				)
+			}
+
+			input.SetIsOpen(true)
+			input.SetOpensAt(opensAt)
+			input.SetClosesAt(closesAt)
+		}
+
+		log.Printf("[DEBUG] Setting maintenance window %d of VPS %d: %+v", weekday, vpsId, input)
+
+		resp, err := update.Call()
+
+		if err != nil {
+			return err
+		} else if !resp.Status {
+			return fmt.Errorf("VPS maintenance window update failed: %s", resp.Message)
+		}
+	}
+
+	return nil
+}
+
+// parseMaintenanceWindowTime converts HH:MM to minutes since midnight
+func parseMaintenanceWindowTime(v string) (int64, error) {
+	parts := strings.Split(v, ":")
+
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("Invalid time '%s', expected HH:MM", v)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid time '%s', expected HH:MM", v)
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid time '%s', expected HH:MM", v)
+	}
+
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("Invalid time '%s', must be between 00:00 and 24:00", v)
+	}
+
+	return int64(hours*60 + minutes), nil
+}
+
+func formatMaintenanceWindowTime(minutes int64) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func validateMaintenanceWindowWeekday(v interface{}, k string) (ws []string, errs []error) {
+	weekday := v.(int)
+
+	if weekday < 0 || weekday >= maintenanceWindowDays {
+		errs = append(errs, fmt.Errorf("%q must be between 0 and 6, got %d", k, weekday))
+	}
+
+	return
+}
+
+func validateMaintenanceWindowTime(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := parseMaintenanceWindowTime(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q: %v", k, err))
+	}
+
+	return
+}
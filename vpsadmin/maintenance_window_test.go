@@ -0,0 +1,151 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestParseMaintenanceWindowTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "00:00", want: 0},
+		{value: "01:30", want: 90},
+		{value: "23:59", want: 1439},
+		{value: "24:00", want: 1440},
+		{value: "24:01", wantErr: true},
+		{value: "12:60", wantErr: true},
+		{value: "1:30", wantErr: true},
+		{value: "0130", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseMaintenanceWindowTime(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseMaintenanceWindowTime(%q) error = nil, want error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("parseMaintenanceWindowTime(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+			if formatted := formatMaintenanceWindowTime(got); formatted != tt.value {
+				t.Fatalf("formatMaintenanceWindowTime(%d) = %q, want %q", got, formatted, tt.value)
+			}
+		})
+	}
+}
+
+func TestFlattenMaintenanceWindowsSkipsClosedDays(t *testing.T) {
+	t.Parallel()
+
+	got := flattenMaintenanceWindows([]*client.ActionVpsMaintenanceWindowIndexOutput{
+		{Weekday: 0, IsOpen: false, OpensAt: 60, ClosesAt: 300},
+		{Weekday: 1, IsOpen: true, OpensAt: 60, ClosesAt: 300},
+	})
+
+	if len(got) != 1 {
+		t.Fatalf("len = %d, want 1", len(got))
+	}
+
+	w := got[0].(map[string]interface{})
+	if w["weekday"] != 1 || w["opens_at"] != "01:00" || w["closes_at"] != "05:00" {
+		t.Fatalf("window = %#v", w)
+	}
+}
+
+func TestSetVpsMaintenanceWindowsClosesUnlistedDays(t *testing.T) {
+	var opened, closed int
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v7.0/vpses/123/maintenance_windows/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		body := readRequestBody(t, r)
+		if strings.Contains(body, `"is_open":true`) {
+			if r.URL.Path != "/v7.0/vpses/123/maintenance_windows/1" {
+				t.Errorf("opened window %s, want weekday 1", r.URL.Path)
+			}
+			opened++
+		} else {
+			closed++
+		}
+
+		writeAPIResponse(t, w, "maintenance_window", &client.ActionVpsMaintenanceWindowUpdateOutput{})
+	})
+
+	err := setVpsMaintenanceWindows(cfg.getClient(), 123, []interface{}{
+		map[string]interface{}{
+			"weekday":   1,
+			"opens_at":  "01:00",
+			"closes_at": "05:00",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opened != 1 || closed != 6 {
+		t.Fatalf("opened = %d, closed = %d, want 1 and 6", opened, closed)
+	}
+}
+
+func TestIsDefaultMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	windows := make([]*client.ActionVpsMaintenanceWindowIndexOutput, 0, maintenanceWindowDays)
+
+	for weekday := 0; weekday < maintenanceWindowDays; weekday++ {
+		windows = append(windows, &client.ActionVpsMaintenanceWindowIndexOutput{
+			Weekday:  int64(weekday),
+			IsOpen:   true,
+			OpensAt:  60,
+			ClosesAt: 300,
+		})
+	}
+
+	if !isDefaultMaintenanceWindows(windows) {
+		t.Fatal("default windows are not recognized")
+	}
+
+	windows[3].IsOpen = false
+
+	if isDefaultMaintenanceWindows(windows) {
+		t.Fatal("closed window is recognized as default")
+	}
+}
+
+func TestDefaultMaintenanceWindowsOpenEveryDay(t *testing.T) {
+	t.Parallel()
+
+	windows := defaultMaintenanceWindows()
+
+	if len(windows) != maintenanceWindowDays {
+		t.Fatalf("len = %d, want %d", len(windows), maintenanceWindowDays)
+	}
+
+	for i, v := range windows {
+		w := v.(map[string]interface{})
+		if w["weekday"] != i || w["opens_at"] != "01:00" || w["closes_at"] != "05:00" {
+			t.Fatalf("window = %#v", w)
+		}
+	}
+}
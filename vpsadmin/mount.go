@@ -3,6 +3,8 @@ package vpsadmin
 import (
 	"fmt"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"strconv"
+	"strings"
 )
 
 func mountShow(api *client.Client, vpsId int, mountId int) (*client.ActionVpsMountShowOutput, error) {
@@ -52,3 +54,43 @@ func mountFindById(api *client.Client, id int) (*client.ActionVpsMountShowOutput
 
 	return nil, fmt.Errorf("Mount not found on any VPS")
 }
+
+func mountFindByMountpoint(api *client.Client, vpsId int, mountpoint string) (*client.ActionVpsMountIndexOutput, error) {
+	list := api.Vps.Mount.Index.Prepare()
+	list.SetPathParamInt("vps_id", int64(vpsId))
+
+	input := list.NewInput()
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list mounts of VPS %d: %s", vpsId, resp.Message)
+	}
+
+	for _, mount := range resp.Output {
+		if mount.Mountpoint == mountpoint {
+			return mount, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Mount at '%s' not found on VPS %d", mountpoint, vpsId)
+}
+
+// parseMountImportId parses import IDs in the form of vps_id/mountpoint
+func parseMountImportId(id string) (int, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", fmt.Errorf("Invalid mount import ID '%s', expected mount ID or vps_id/mountpoint", id)
+	}
+
+	vpsId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("Invalid VPS id in '%s': %v", id, err)
+	}
+
+	return vpsId, "/" + strings.TrimLeft(parts[1], "/"), nil
+}
@@ -0,0 +1,152 @@
+package vpsadmin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestParseMountImportId(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id             string
+		wantVps        int
+		wantMountpoint string
+		wantErr        bool
+	}{
+		{id: "123/mnt/backup", wantVps: 123, wantMountpoint: "/mnt/backup"},
+		{id: "123//mnt/backup", wantVps: 123, wantMountpoint: "/mnt/backup"},
+		{id: "123/", wantErr: true},
+		{id: "web1/mnt/backup", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+
+			vpsId, mountpoint, err := parseMountImportId(tt.id)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseMountImportId(%q) error = nil, want error", tt.id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if vpsId != tt.wantVps || mountpoint != tt.wantMountpoint {
+				t.Fatalf(
+					"parseMountImportId(%q) = %d, %q, want %d, %q",
+					tt.id,
+					vpsId,
+					mountpoint,
+					tt.wantVps,
+					tt.wantMountpoint,
+				)
+			}
+		})
+	}
+}
+
+func TestMountFindByMountpoint(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/vpses/123/mounts" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "mount[limit]", strconv.Itoa(apiPageLimit))
+		writeAPIResponse(t, w, "mounts", []*client.ActionVpsMountIndexOutput{
+			{Id: 98, Mountpoint: "/mnt/data"},
+			{Id: 99, Mountpoint: "/mnt/backup"},
+		})
+	})
+
+	mount, err := mountFindByMountpoint(cfg.getClient(), 123, "/mnt/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mount.Id != 99 {
+		t.Fatalf("mount id = %d, want 99", mount.Id)
+	}
+
+	if _, err := mountFindByMountpoint(cfg.getClient(), 123, "/srv"); err == nil {
+		t.Fatal("mountFindByMountpoint(/srv) error = nil, want not found")
+	}
+}
+
+func TestResourceMountCreateMountsSnapshotReadOnly(t *testing.T) {
+	var createBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123/mounts":
+			createBody = readRequestBody(t, r)
+			writeAPIResponse(t, w, "mount", &client.ActionVpsMountCreateOutput{Id: 99})
+		case "/v7.0/vpses/123/mounts/99":
+			writeAPIResponse(t, w, "mount", &client.ActionVpsMountShowOutput{
+				Id:          99,
+				Dataset:     &client.ActionDatasetShowOutput{Id: 42},
+				Snapshot:    &client.ActionDatasetSnapshotShowOutput{Id: 5},
+				Mountpoint:  "/mnt/backup",
+				Mode:        "ro",
+				Enabled:     true,
+				OnStartFail: "mount_later",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMount().Schema, map[string]interface{}{
+		"vps":        123,
+		"snapshot":   5,
+		"mountpoint": "/mnt/backup",
+	})
+
+	if err := resourceMountCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"snapshot":5`, `"mode":"ro"`} {
+		if !strings.Contains(createBody, want) {
+			t.Fatalf("create body = %s, want %s", createBody, want)
+		}
+	}
+	if strings.Contains(createBody, `"dataset"`) {
+		t.Fatalf("create body = %s, must not contain dataset", createBody)
+	}
+	assertResourceValue(t, d, "dataset", 42)
+	assertResourceValue(t, d, "snapshot", 5)
+	assertResourceValue(t, d, "mode", "ro")
+}
+
+func TestResourceMountDiffRejectsReadWriteSnapshot(t *testing.T) {
+	state := &terraform.InstanceState{
+		RawConfig: testRawConfig(resourceMount(), map[string]cty.Value{
+			"vps":        cty.NumberIntVal(123),
+			"snapshot":   cty.NumberIntVal(5),
+			"mountpoint": cty.StringVal("/mnt/backup"),
+			"mode":       cty.StringVal("rw"),
+		}),
+	}
+
+	_, err := resourceMount().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"vps":        123,
+		"snapshot":   5,
+		"mountpoint": "/mnt/backup",
+		"mode":       "rw",
+	}), nil)
+	if err == nil || !strings.Contains(err.Error(), "read-only mode") {
+		t.Fatalf("err = %v, want read-only error", err)
+	}
+}
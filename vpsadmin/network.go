@@ -0,0 +1,101 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+// networkFilter selects networks or IP addresses, zero values are ignored
+type networkFilter struct {
+	locationId int64
+	ipVersion  int
+	role       string
+	purpose    string
+}
+
+func networkFilterSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["location"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Location label",
+		Optional:    true,
+	}
+	s["version"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Description:  "IP version, 4 or 6",
+		Optional:     true,
+		ValidateFunc: validation.IntInSlice([]int{4, 6}),
+	}
+	s["role"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "Network role, public_access or private_access",
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"public_access", "private_access"}, false),
+	}
+	s["purpose"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "Network purpose, any, vps or export",
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"any", "vps", "export"}, false),
+	}
+
+	return s
+}
+
+func getNetworkFilter(api *client.Client, d *schema.ResourceData) (networkFilter, error) {
+	filter := networkFilter{
+		ipVersion: d.Get("version").(int),
+		role:      d.Get("role").(string),
+		purpose:   d.Get("purpose").(string),
+	}
+
+	if v, ok := d.GetOk("location"); ok {
+		locationId, err := getLocationIdByLabel(api, v.(string))
+		if err != nil {
+			return filter, err
+		}
+
+		filter.locationId = locationId
+	}
+
+	return filter, nil
+}
+
+func networkList(api *client.Client, filter networkFilter) ([]*client.ActionNetworkIndexOutput, error) {
+	action := api.Network.Index.Prepare()
+
+	input := action.NewInput()
+	input.SetLimit(apiPageLimit)
+
+	if filter.locationId != 0 {
+		input.SetLocation(filter.locationId)
+	}
+
+	if filter.purpose != "" {
+		input.SetPurpose(filter.purpose)
+	}
+
+	resp, err := action.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list networks: %s", resp.Message)
+	}
+
+	// The API cannot filter networks by version and role
+	ret := make([]*client.ActionNetworkIndexOutput, 0, len(resp.Output))
+
+	for _, network := range resp.Output {
+		if filter.ipVersion != 0 && int(network.IpVersion) != filter.ipVersion {
+			continue
+		} else if filter.role != "" && network.Role != filter.role {
+			continue
+		}
+
+		ret = append(ret, network)
+	}
+
+	return ret, nil
+}
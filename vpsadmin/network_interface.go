@@ -0,0 +1,230 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+// getVpsNetworkInterface returns the network interface of a VPS, optionally
+// selected by id or name. Fails if the selection matches more than one
+// interface.
+func getVpsNetworkInterface(api *client.Client, vpsId int64, id int64, name string) (*client.ActionNetworkInterfaceIndexOutput, error) {
+	netifs, err := vpsNetworkInterfaceList(api, vpsId)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*client.ActionNetworkInterfaceIndexOutput
+
+	for _, netif := range netifs {
+		if id != 0 && netif.Id != id {
+			continue
+		} else if name != "" && netif.Name != name {
+			continue
+		}
+
+		found = append(found, netif)
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("Network interface not found in VPS %d", vpsId)
+	} else if len(found) > 1 {
+		return nil, fmt.Errorf(
+			"VPS %d has %d network interfaces, select one by its ID or name",
+			vpsId, len(found),
+		)
+	}
+
+	return found[0], nil
+}
+
+func networkInterfaceShow(api *client.Client, id int64) (*client.ActionNetworkInterfaceShowOutput, error) {
+	show := api.NetworkInterface.Show.Prepare()
+	show.SetPathParamInt("network_interface_id", id)
+
+	resp, err := show.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Network interface show failed: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+// networkInterfaceSchema returns attributes shared by the network interface
+// resource and data source, the data source has them all computed
+func networkInterfaceSchema(computed bool) map[string]*schema.Schema {
+	ret := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Interface name as seen inside the VPS, e.g. eth0",
+			Optional:    !computed,
+			Computed:    true,
+		},
+		"type": &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Interface type",
+			Computed:    true,
+		},
+		"mac": &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "MAC address",
+			Computed:    true,
+		},
+		"max_tx": &schema.Schema{
+			Type:         schema.TypeInt,
+			Description:  "Max outgoing data throughput, 0 for unlimited",
+			Optional:     !computed,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_rx": &schema.Schema{
+			Type:         schema.TypeInt,
+			Description:  "Max incoming data throughput, 0 for unlimited",
+			Optional:     !computed,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+	}
+
+	if computed {
+		for _, attr := range ret {
+			attr.ValidateFunc = nil
+		}
+	}
+
+	return ret
+}
+
+func setNetworkInterfaceAttributes(d *schema.ResourceData, netif *client.ActionNetworkInterfaceShowOutput) {
+	d.Set("interface_id", netif.Id)
+	d.Set("name", netif.Name)
+	d.Set("type", netif.Type)
+	d.Set("mac", netif.Mac)
+	d.Set("max_tx", netif.MaxTx)
+	d.Set("max_rx", netif.MaxRx)
+}
+
+func vpsNetworkInterfaceList(api *client.Client, vpsId int64) ([]*client.ActionNetworkInterfaceIndexOutput, error) {
+	list := api.NetworkInterface.Index.Prepare()
+
+	input := list.NewInput()
+	input.SetVps(vpsId)
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list network interfaces: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+// networkConfigSchema describes network configuration of a VPS
+func networkConfigSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Network interfaces of the VPS with their addresses and routes. There are no gateways, interfaces are routed point-to-point and vpsAdmin configures the default route through the interface itself",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Interface name",
+					Computed:    true,
+				},
+				"type": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "Interface type",
+					Computed:    true,
+				},
+				"mac": &schema.Schema{
+					Type:        schema.TypeString,
+					Description: "MAC address",
+					Computed:    true,
+				},
+				"addresses": &schema.Schema{
+					Type:        schema.TypeList,
+					Description: "Host addresses configured on the interface, in CIDR notation",
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+				"routes": &schema.Schema{
+					Type:        schema.TypeList,
+					Description: "Addresses routed to the interface",
+					Computed:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"destination": &schema.Schema{
+								Type:        schema.TypeString,
+								Description: "Routed address in CIDR notation",
+								Computed:    true,
+							},
+							"via": &schema.Schema{
+								Type:        schema.TypeString,
+								Description: "Host address the route goes through, empty if routed directly",
+								Computed:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flattenNetworkConfig groups host addresses and their routed addresses
+// by network interface
+func flattenNetworkConfig(
+	netifs []*client.ActionNetworkInterfaceIndexOutput,
+	hostAddrs []*client.ActionHostIpAddressIndexOutput,
+) []interface{} {
+	ret := make([]interface{}, 0, len(netifs))
+
+	for _, netif := range netifs {
+		addresses := make([]interface{}, 0)
+		routes := make([]interface{}, 0)
+		seenRoutes := make(map[int64]bool)
+
+		for _, addr := range hostAddrs {
+			ip := addr.IpAddress
+			if ip == nil || ip.NetworkInterface == nil || ip.NetworkInterface.Id != netif.Id {
+				continue
+			}
+
+			addresses = append(addresses, fmt.Sprintf("%s/%d", addr.Addr, ip.Prefix))
+
+			if seenRoutes[ip.Id] {
+				continue
+			}
+			seenRoutes[ip.Id] = true
+
+			via := ""
+			if ip.RouteVia != nil {
+				via = ip.RouteVia.Addr
+			}
+
+			routes = append(routes, map[string]interface{}{
+				"destination": fmt.Sprintf("%s/%d", ip.Addr, ip.Prefix),
+				"via":         via,
+			})
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"name":      netif.Name,
+			"type":      netif.Type,
+			"mac":       netif.Mac,
+			"addresses": addresses,
+			"routes":    routes,
+		})
+	}
+
+	return ret
+}
@@ -0,0 +1,97 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestGetVpsNetworkInterfaceSelectsInterface(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		assertQueryValue(t, r, "network_interface[vps]", "123")
+		writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+			{Id: 55, Name: "venet0"},
+			{Id: 56, Name: "eth1"},
+		})
+	})
+
+	tests := []struct {
+		name    string
+		id      int64
+		netif   string
+		wantId  int64
+		wantErr string
+	}{
+		{name: "by id", id: 56, wantId: 56},
+		{name: "by name", netif: "venet0", wantId: 55},
+		{name: "ambiguous", wantErr: "has 2 network interfaces"},
+		{name: "not found", netif: "eth0", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		got, err := getVpsNetworkInterface(cfg.getClient(), 123, tt.id, tt.netif)
+
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if got.Id != tt.wantId {
+			t.Fatalf("%s: id = %d, want %d", tt.name, got.Id, tt.wantId)
+		}
+	}
+}
+
+func TestFlattenNetworkConfigGroupsAddressesByInterface(t *testing.T) {
+	t.Parallel()
+
+	routed := &client.ActionIpAddressShowOutput{
+		Id:               9,
+		Addr:             "2001:db8:1::",
+		Prefix:           64,
+		NetworkInterface: &client.ActionNetworkInterfaceShowOutput{Id: 55},
+		RouteVia:         &client.ActionHostIpAddressShowOutput{Addr: "2001:db8::1"},
+	}
+
+	config := flattenNetworkConfig(
+		[]*client.ActionNetworkInterfaceIndexOutput{
+			{Id: 55, Name: "eth0"},
+			{Id: 56, Name: "eth1"},
+		},
+		[]*client.ActionHostIpAddressIndexOutput{
+			{Addr: "2001:db8:1::1", IpAddress: routed},
+			{Addr: "2001:db8:1::2", IpAddress: routed},
+		},
+	)
+
+	if len(config) != 2 {
+		t.Fatalf("config = %v, want 2 interfaces", config)
+	}
+
+	eth0 := config[0].(map[string]interface{})
+	addresses := eth0["addresses"].([]interface{})
+	routes := eth0["routes"].([]interface{})
+
+	if len(addresses) != 2 || addresses[1] != "2001:db8:1::2/64" {
+		t.Fatalf("eth0 addresses = %v", addresses)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("eth0 routes = %v, want 1 route", routes)
+	}
+
+	route := routes[0].(map[string]interface{})
+	if route["destination"] != "2001:db8:1::/64" || route["via"] != "2001:db8::1" {
+		t.Fatalf("eth0 route = %v", route)
+	}
+
+	eth1 := config[1].(map[string]interface{})
+	if len(eth1["addresses"].([]interface{})) != 0 {
+		t.Fatalf("eth1 addresses = %v, want none", eth1["addresses"])
+	}
+}
@@ -0,0 +1,94 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestNetworkListFiltersVersionAndRole(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/networks" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "network[limit]", strconv.Itoa(apiPageLimit))
+		assertQueryValue(t, r, "network[location]", "3")
+		writeAPIResponse(t, w, "networks", []*client.ActionNetworkIndexOutput{
+			{Id: 1, IpVersion: 4, Role: "public_access"},
+			{Id: 2, IpVersion: 4, Role: "private_access"},
+			{Id: 3, IpVersion: 6, Role: "public_access"},
+		})
+	})
+
+	networks, err := networkList(cfg.getClient(), networkFilter{
+		locationId: 3,
+		ipVersion:  4,
+		role:       "public_access",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(networks) != 1 || networks[0].Id != 1 {
+		t.Fatalf("networks = %+v, want only network 1", networks)
+	}
+}
+
+func TestDataSourceNetworksComputesFreeAddresses(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "networks", []*client.ActionNetworkIndexOutput{
+			{Id: 1, Address: "198.51.100.0", Prefix: 24, IpVersion: 4, Used: 200, Taken: 150},
+		})
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetworks().Schema, map[string]interface{}{})
+
+	if err := dataSourceNetworksRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "networks.#", 1)
+	assertResourceValue(t, d, "networks.0.address", "198.51.100.0")
+	assertResourceValue(t, d, "networks.0.free", 50)
+}
+
+func TestDataSourceFreeIpAddressesRead(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/ip_addresses" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "ip_address[assigned_to_interface]", "0")
+		assertQueryValue(t, r, "ip_address[version]", "6")
+		assertQueryValue(t, r, "ip_address[limit]", "5")
+		if _, ok := r.URL.Query()["ip_address[role]"]; ok {
+			t.Error("ip_address[role] must be omitted")
+		}
+
+		writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+			{Id: 9, Addr: "2001:db8:1::", Prefix: 64, Network: &client.ActionNetworkShowOutput{Id: 3}},
+			{Id: 10, Addr: "2001:db8:2::", Prefix: 64, User: &client.ActionUserShowOutput{Id: 7}},
+		})
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceFreeIpAddresses().Schema, map[string]interface{}{
+		"version": 6,
+		"limit":   5,
+	})
+
+	if err := dataSourceFreeIpAddressesRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "addresses.#", 2)
+	assertResourceValue(t, d, "addresses.0.address", "2001:db8:1::")
+	assertResourceValue(t, d, "addresses.0.network", 3)
+	assertResourceValue(t, d, "addresses.0.owned", false)
+	assertResourceValue(t, d, "addresses.1.owned", true)
+}
@@ -24,16 +24,32 @@ func Provider() *schema.Provider {
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"vpsadmin_dataset": dataSourceDataset(),
-			"vpsadmin_mount":   dataSourceMount(),
-			"vpsadmin_ssh_key": dataSourceSshKey(),
-			"vpsadmin_vps":     dataSourceVps(),
+			"vpsadmin_dataset":           dataSourceDataset(),
+			"vpsadmin_dataset_snapshots": dataSourceDatasetSnapshots(),
+			"vpsadmin_dns_resolvers":     dataSourceDnsResolvers(),
+			"vpsadmin_free_ip_addresses": dataSourceFreeIpAddresses(),
+			"vpsadmin_ip_traffic":        dataSourceIpTraffic(),
+			"vpsadmin_mount":             dataSourceMount(),
+			"vpsadmin_network_interface": dataSourceNetworkInterface(),
+			"vpsadmin_networks":          dataSourceNetworks(),
+			"vpsadmin_ssh_key":           dataSourceSshKey(),
+			"vpsadmin_vps":               dataSourceVps(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"vpsadmin_dataset": resourceDataset(),
-			"vpsadmin_mount":   resourceMount(),
-			"vpsadmin_ssh_key": resourceSshKey(),
-			"vpsadmin_vps":     resourceVps(),
+			"vpsadmin_dataset":                resourceDataset(),
+			"vpsadmin_dataset_plan":           resourceDatasetPlan(),
+			"vpsadmin_dataset_rollback":       resourceDatasetRollback(),
+			"vpsadmin_dataset_snapshot":       resourceDatasetSnapshot(),
+			"vpsadmin_export_host":            resourceExportHost(),
+			"vpsadmin_host_ip_address":        resourceHostIpAddress(),
+			"vpsadmin_host_ip_reverse_record": resourceHostIpReverseRecord(),
+			"vpsadmin_ip_address":             resourceIpAddress(),
+			"vpsadmin_ip_reservation":         resourceIpReservation(),
+			"vpsadmin_mount":                  resourceMount(),
+			"vpsadmin_network_interface":      resourceNetworkInterface(),
+			"vpsadmin_snapshot_download":      resourceSnapshotDownload(),
+			"vpsadmin_ssh_key":                resourceSshKey(),
+			"vpsadmin_vps":                    resourceVps(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -50,7 +50,13 @@ func TestProviderRegistersExpectedDataSources(t *testing.T) {
 
 	assertMapKeys(t, provider.DataSourcesMap, []string{
 		"vpsadmin_dataset",
+		"vpsadmin_dataset_snapshots",
+		"vpsadmin_dns_resolvers",
+		"vpsadmin_free_ip_addresses",
+		"vpsadmin_ip_traffic",
 		"vpsadmin_mount",
+		"vpsadmin_network_interface",
+		"vpsadmin_networks",
 		"vpsadmin_ssh_key",
 		"vpsadmin_vps",
 	})
@@ -73,7 +79,17 @@ func TestProviderRegistersExpectedResources(t *testing.T) {
 
 	assertMapKeys(t, provider.ResourcesMap, []string{
 		"vpsadmin_dataset",
+		"vpsadmin_dataset_plan",
+		"vpsadmin_dataset_rollback",
+		"vpsadmin_dataset_snapshot",
+		"vpsadmin_export_host",
+		"vpsadmin_host_ip_address",
+		"vpsadmin_host_ip_reverse_record",
+		"vpsadmin_ip_address",
+		"vpsadmin_ip_reservation",
 		"vpsadmin_mount",
+		"vpsadmin_network_interface",
+		"vpsadmin_snapshot_download",
 		"vpsadmin_ssh_key",
 		"vpsadmin_vps",
 	})
@@ -88,7 +104,7 @@ func TestProviderRegistersExpectedResources(t *testing.T) {
 		if resource.Read == nil {
 			t.Fatalf("resource %q has no Read function", name)
 		}
-		if resource.Update == nil {
+		if resource.Update == nil && hasUpdatableAttributes(resource) {
 			t.Fatalf("resource %q has no Update function", name)
 		}
 		if resource.Delete == nil {
@@ -97,6 +113,18 @@ func TestProviderRegistersExpectedResources(t *testing.T) {
 	}
 }
 
+// hasUpdatableAttributes returns true if the resource has configurable
+// attributes that do not force a new resource
+func hasUpdatableAttributes(resource *schema.Resource) bool {
+	for _, attr := range resource.Schema {
+		if (attr.Required || attr.Optional) && !attr.ForceNew {
+			return true
+		}
+	}
+
+	return false
+}
+
 func assertMapKeys[V any](t *testing.T, got map[string]V, want []string) {
 	t.Helper()
 
@@ -1,8 +1,10 @@
 package vpsadmin
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
 	"log"
 	"strconv"
@@ -17,6 +19,7 @@ func resourceDataset() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceDatasetImport,
 		},
+		CustomizeDiff: resourceDatasetCustomizeDiff,
 
 		Description: `
 Represents a ZFS dataset, either on NAS (Network-Attached Storage) or a VPS
@@ -24,17 +27,48 @@ subdataset.
 
 NAS datasets can have *quota*, whereas VPS subdatasets use *refquota*
 attribute. NAS datasets can be exported over NFS using *export_dataset*
-and then mounted inside the container. VPS subdatasets are mounted using
-the *vpsadmin_mount* resource.
+and then mounted inside the container. Access to the export can be restricted
+to selected addresses using *export_all_vps* and *vpsadmin_export_host*.
+VPS subdatasets are mounted using the *vpsadmin_mount* resource.
+
+Nested datasets can be created either using their full *name*, or using
+*parent_dataset* and *name* relative to the parent. Only *parent_dataset*
+is checked to exist when planning, parents within the full *name* are not
+validated, missing intermediate datasets are created by vpsAdmin.
+
+ZFS properties *compression*, *recordsize*, *atime*, *relatime* and *sync*
+which are not set are inherited from the parent dataset. When an unset property
+of an existing dataset differs from the parent, e.g. after an import, the plan
+shows it changing to the parent's value, set it in the configuration to keep it.
+Top-level datasets have no parent dataset in vpsAdmin, their unset properties
+are left as they are.
 `,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:        schema.TypeString,
-				Description: "Dataset name",
+				Description: "Dataset name, full or relative to parent_dataset",
 				Required:    true,
 				ForceNew:    true,
 			},
+			"parent_dataset": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the parent dataset, name is then relative to it",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"user_namespace_map": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the user namespace map applied to the dataset, the current map is kept if not set, set to 0 to remove it",
+				Optional:    true,
+				Computed:    true,
+			},
+			"vps": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the VPS the dataset belongs to, 0 for NAS datasets",
+				Computed:    true,
+			},
 			"full_name": &schema.Schema{
 				Type:        schema.TypeString,
 				Description: "Full dataset name",
@@ -69,28 +103,41 @@ the *vpsadmin_mount* resource.
 			},
 			"compression": &schema.Schema{
 				Type:        schema.TypeBool,
-				Description: "Compression enabled",
+				Description: "Compression enabled, inherited from the parent dataset if not set",
+				Optional:    true,
 				Computed:    true,
 			},
 			"recordsize": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "Record size, in bytes",
-				Computed:    true,
+				Type:         schema.TypeInt,
+				Description:  "Record size in bytes, a power of two from 4096 to 1048576, inherited from the parent dataset if not set",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateDatasetRecordsize,
 			},
 			"atime": &schema.Schema{
 				Type:        schema.TypeBool,
-				Description: "Enabled atime",
+				Description: "Enabled atime, inherited from the parent dataset if not set",
+				Optional:    true,
 				Computed:    true,
 			},
 			"relatime": &schema.Schema{
 				Type:        schema.TypeBool,
-				Description: "Enabled relatime",
+				Description: "Enabled relatime, inherited from the parent dataset if not set",
+				Optional:    true,
 				Computed:    true,
 			},
 			"sync": &schema.Schema{
-				Type:        schema.TypeString,
-				Description: "Sync mode",
+				Type:         schema.TypeString,
+				Description:  "Sync mode, standard or disabled, inherited from the parent dataset if not set",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"standard", "disabled"}, false),
+			},
+			"plans": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Names of active dataset plans, see vpsadmin_dataset_plan",
 				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"export_dataset": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -112,6 +159,15 @@ the *vpsadmin_mount* resource.
 					return !d.Get("export_dataset").(bool)
 				},
 			},
+			"export_all_vps": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Allow all VPS of the user to mount the export, set to false to allow only addresses from vpsadmin_export_host",
+				Default:     true,
+				Optional:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return !d.Get("export_dataset").(bool)
+				},
+			},
 			"export_root_squash": &schema.Schema{
 				Type:        schema.TypeBool,
 				Description: "Enable root squash on the export",
@@ -149,10 +205,60 @@ the *vpsadmin_mount* resource.
 				Description: "Path to mount from the NFS server",
 				Computed:    true,
 			},
+			"deletion_protection": deletionProtectionSchema("dataset"),
 		},
 	}
 }
 
+func resourceDatasetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" && m != nil && d.NewValueKnown("parent_dataset") {
+		if parent, ok := d.GetOk("parent_dataset"); ok {
+			api := m.(*Config).getClient()
+
+			if _, err := datasetShow(api, parent.(int)); err != nil {
+				return fmt.Errorf("Parent dataset %d not found: %v", parent.(int), err)
+			}
+		}
+	}
+
+	parentId := d.Get("parent_dataset").(int)
+
+	if d.Id() == "" || m == nil || parentId == 0 || !d.NewValueKnown("parent_dataset") {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	var parent *client.ActionDatasetShowOutput
+
+	// Unset properties are going to be inherited, plan the parent's values
+	for _, name := range datasetProperties {
+		if datasetPropertyConfigured(config, name) {
+			continue
+		}
+
+		if parent == nil {
+			var err error
+
+			parent, err = datasetShow(m.(*Config).getClient(), parentId)
+			if err != nil {
+				return err
+			}
+		}
+
+		if v := datasetPropertyValue(parent, name); d.Get(name) != v {
+			if err := d.SetNew(name, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func resourceDatasetCreate(d *schema.ResourceData, m interface{}) error {
 	api := m.(*Config).getClient()
 
@@ -161,6 +267,10 @@ func resourceDatasetCreate(d *schema.ResourceData, m interface{}) error {
 	input := create.NewInput()
 	input.SetName(d.Get("name").(string))
 
+	if v, ok := d.GetOk("parent_dataset"); ok {
+		input.SetDataset(int64(v.(int)))
+	}
+
 	if v, ok := d.GetOk("quota"); ok {
 		input.SetQuota(int64(v.(int)))
 	}
@@ -169,6 +279,14 @@ func resourceDatasetCreate(d *schema.ResourceData, m interface{}) error {
 		input.SetRefquota(int64(v.(int)))
 	}
 
+	config := d.GetRawConfig()
+
+	for _, name := range datasetProperties {
+		if datasetPropertyConfigured(config, name) {
+			setDatasetCreateProperty(input, name, d.Get(name))
+		}
+	}
+
 	resp, err := create.Call()
 
 	if err != nil {
@@ -183,6 +301,12 @@ func resourceDatasetCreate(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
 
+	if v, ok := d.GetOk("user_namespace_map"); ok {
+		if err := setDatasetUserNamespaceMap(api, resp.Output.Id, int64(v.(int))); err != nil {
+			return err
+		}
+	}
+
 	if d.Get("export_dataset").(bool) {
 		if err := createDatasetExport(api, resp.Output.Id, d); err != nil {
 			return err
@@ -207,6 +331,25 @@ func resourceDatasetRead(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(strconv.Itoa(id))
 	d.Set("full_name", ds.Name)
+
+	if ds.Parent != nil {
+		d.Set("parent_dataset", ds.Parent.Id)
+	} else {
+		d.Set("parent_dataset", nil)
+	}
+
+	if ds.UserNamespaceMap != nil {
+		d.Set("user_namespace_map", ds.UserNamespaceMap.Id)
+	} else {
+		d.Set("user_namespace_map", nil)
+	}
+
+	if ds.Vps != nil {
+		d.Set("vps", ds.Vps.Id)
+	} else {
+		d.Set("vps", nil)
+	}
+
 	d.Set("used", ds.Used)
 	d.Set("referenced", ds.Referenced)
 	d.Set("avail", ds.Avail)
@@ -218,10 +361,18 @@ func resourceDatasetRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("relatime", ds.Relatime)
 	d.Set("sync", ds.Sync)
 
+	plans, err := datasetPlanNames(api, ds.Id)
+	if err != nil {
+		return err
+	}
+
+	d.Set("plans", plans)
+
 	if ds.Export != nil {
 		d.Set("export_dataset", true)
 		d.Set("export_id", ds.Export.Id)
 		d.Set("export_enable", ds.Export.Enabled)
+		d.Set("export_all_vps", ds.Export.AllVps)
 		d.Set("export_root_squash", ds.Export.RootSquash)
 		d.Set("export_read_write", ds.Export.Rw)
 		d.Set("export_sync", ds.Export.Sync)
@@ -256,6 +407,29 @@ func resourceDatasetUpdate(d *schema.ResourceData, m interface{}) error {
 		input.SetRefquota(int64(d.Get("refquota").(int)))
 	}
 
+	if d.HasChange("user_namespace_map") {
+		oldMap, newMap := d.GetChange("user_namespace_map")
+
+		if newMap.(int) != 0 {
+			input.SetUserNamespaceMap(int64(newMap.(int)))
+		} else if oldMap.(int) != 0 {
+			input.SetUserNamespaceMapNil(true)
+		}
+	}
+
+	config := d.GetRawConfig()
+	inherit := make([]string, 0)
+
+	for _, name := range datasetProperties {
+		if !d.HasChange(name) {
+			continue
+		} else if !config.IsNull() && !datasetPropertyConfigured(config, name) {
+			inherit = append(inherit, name)
+		} else {
+			setDatasetUpdateProperty(input, name, d.Get(name))
+		}
+	}
+
 	if input.AnySelected() {
 		updateResp, err := dsUpdate.Call()
 
@@ -270,6 +444,12 @@ func resourceDatasetUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	for _, name := range inherit {
+		if err := inheritDatasetProperty(api, int64(id), name); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("export_dataset") {
 		ds, err := datasetShow(api, id)
 		if err != nil {
@@ -288,7 +468,7 @@ func resourceDatasetUpdate(d *schema.ResourceData, m interface{}) error {
 			}
 		}
 	} else {
-		if d.HasChanges("export_enable", "export_root_squash", "export_read_write", "export_sync") {
+		if d.HasChanges("export_enable", "export_all_vps", "export_root_squash", "export_read_write", "export_sync") {
 			ds, err := datasetShow(api, id)
 			if err != nil {
 				return err
@@ -313,6 +493,10 @@ func resourceDatasetDelete(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("Invalid dataset id: %v", err)
 	}
 
+	if err := checkDeletionProtection(d, "Dataset"); err != nil {
+		return err
+	}
+
 	ds, err := datasetShow(api, id)
 	if err != nil {
 		return err
@@ -363,6 +547,7 @@ func resourceDatasetImport(d *schema.ResourceData, m interface{}) ([]*schema.Res
 
 	d.Set("name", d.Id())
 	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
+	d.Set("deletion_protection", false)
 
 	if err := resourceDatasetRead(d, m); err != nil {
 		return nil, fmt.Errorf("invalid dataset id: %v", err)
@@ -380,6 +565,7 @@ func createDatasetExport(api *client.Client, datasetId int64, d *schema.Resource
 	input := create.NewInput()
 	input.SetDataset(datasetId)
 	input.SetEnabled(d.Get("export_enable").(bool))
+	input.SetAllVps(d.Get("export_all_vps").(bool))
 	input.SetRootSquash(d.Get("export_root_squash").(bool))
 	input.SetRw(d.Get("export_read_write").(bool))
 	input.SetSync(d.Get("export_sync").(bool))
@@ -409,6 +595,10 @@ func updateDatasetExport(api *client.Client, id int64, d *schema.ResourceData) e
 		input.SetEnabled(d.Get("export_enable").(bool))
 	}
 
+	if d.HasChange("export_all_vps") {
+		input.SetAllVps(d.Get("export_all_vps").(bool))
+	}
+
 	if d.HasChange("export_root_squash") {
 		input.SetRootSquash(d.Get("export_root_squash").(bool))
 	}
@@ -0,0 +1,244 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"strconv"
+	"strings"
+)
+
+func resourceDatasetPlan() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatasetPlanCreate,
+		Read:   resourceDatasetPlanRead,
+		Delete: resourceDatasetPlanDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDatasetPlanImport,
+		},
+
+		Description: `
+Enables a dataset plan provided by the environment, e.g. daily backups, on
+a dataset or a VPS root dataset. Active plans can be read from *plans*
+of *vpsadmin_dataset*.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"dataset": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Dataset ID, e.g. from vpsadmin_dataset",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"vps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "VPS ID, enables the plan on the VPS root dataset",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"plan": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Dataset plan name, e.g. daily_backup",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"environment": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Environment label, needed only for datasets which do not belong to a VPS",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"label": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Dataset plan label",
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Dataset plan description",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceDatasetPlanCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	var datasetId, envId int64
+
+	if v, ok := d.GetOk("vps"); ok {
+		vps, err := vpsShow(api, v.(int))
+		if err != nil {
+			return err
+		}
+
+		if vps.Dataset == nil {
+			return fmt.Errorf("VPS %d has no root dataset", vps.Id)
+		}
+
+		datasetId = vps.Dataset.Id
+
+		envId, err = getVpsEnvironmentId(vps)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+
+		datasetId = int64(d.Get("dataset").(int))
+
+		if label, ok := d.GetOk("environment"); ok {
+			envId, err = getEnvironmentIdByLabel(api, label.(string))
+		} else {
+			envId, err = getDatasetEnvironmentId(api, datasetId)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	envPlan, err := findEnvironmentDatasetPlan(api, envId, d.Get("plan").(string))
+	if err != nil {
+		return err
+	}
+
+	if !envPlan.UserAdd {
+		return fmt.Errorf("Dataset plan '%s' cannot be enabled by users", d.Get("plan").(string))
+	}
+
+	create := api.Dataset.Plan.Create.Prepare()
+	create.SetPathParamInt("dataset_id", datasetId)
+
+	input := create.NewInput()
+	input.SetEnvironmentDatasetPlan(envPlan.Id)
+
+	log.Printf("[INFO] Enabling dataset plan %s on dataset %d", d.Get("plan").(string), datasetId)
+
+	resp, err := create.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Dataset plan creation failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Dataset plan creation failed: %v", err)
+	}
+
+	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
+	d.Set("dataset", datasetId)
+
+	return resourceDatasetPlanRead(d, m)
+}
+
+func resourceDatasetPlanRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid dataset plan id: %v", err)
+	}
+
+	plans, err := datasetPlanList(api, int64(d.Get("dataset").(int)))
+	if err != nil {
+		return err
+	}
+
+	var plan *client.ActionDatasetPlanIndexOutput
+
+	for _, p := range plans {
+		if p.Id == id {
+			plan = p
+			break
+		}
+	}
+
+	if plan == nil {
+		log.Printf("[INFO] Dataset plan %d not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	if envPlan := plan.EnvironmentDatasetPlan; envPlan != nil && envPlan.DatasetPlan != nil {
+		d.Set("plan", envPlan.DatasetPlan.Name)
+		d.Set("label", envPlan.DatasetPlan.Label)
+		d.Set("description", envPlan.DatasetPlan.Description)
+	}
+
+	return nil
+}
+
+func resourceDatasetPlanDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid dataset plan id: %v", err)
+	}
+
+	log.Printf("[INFO] Disabling dataset plan: %s", d.Id())
+
+	del := api.Dataset.Plan.Delete.Prepare()
+	del.SetPathParamInt("dataset_id", int64(d.Get("dataset").(int)))
+	del.SetPathParamInt("plan_id", id)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Dataset plan deletion failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Dataset plan deletion failed: %v", err)
+	}
+
+	return nil
+}
+
+func resourceDatasetPlanImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	api := m.(*Config).getClient()
+
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid dataset plan import ID '%s', expected dataset_id/plan", d.Id())
+	}
+
+	datasetId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid dataset id in '%s': %v", d.Id(), err)
+	}
+
+	plans, err := datasetPlanList(api, int64(datasetId))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		envPlan := plan.EnvironmentDatasetPlan
+
+		if envPlan != nil && envPlan.DatasetPlan != nil && envPlan.DatasetPlan.Name == parts[1] {
+			d.SetId(strconv.FormatInt(plan.Id, 10))
+			d.Set("dataset", datasetId)
+
+			if err := resourceDatasetPlanRead(d, m); err != nil {
+				return nil, fmt.Errorf("invalid dataset plan id: %v", err)
+			}
+
+			results := make([]*schema.ResourceData, 1)
+			results[0] = d
+
+			return results, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Dataset plan '%s' is not enabled on dataset %d", parts[1], datasetId)
+}
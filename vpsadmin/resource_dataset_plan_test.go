@@ -0,0 +1,135 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func testEnvironmentDatasetPlans() []*client.ActionEnvironmentDatasetPlanIndexOutput {
+	return []*client.ActionEnvironmentDatasetPlanIndexOutput{
+		{
+			Id:          11,
+			DatasetPlan: &client.ActionDatasetPlanShowOutput{Name: "daily_backup"},
+			UserAdd:     true,
+			UserRemove:  true,
+		},
+		{
+			Id:          12,
+			DatasetPlan: &client.ActionDatasetPlanShowOutput{Name: "hourly_backup"},
+		},
+	}
+}
+
+func TestResourceDatasetPlanCreateOnVpsRootDataset(t *testing.T) {
+	var createBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:      123,
+				Dataset: &client.ActionDatasetShowOutput{Id: 42},
+				Node: &client.ActionNodeShowOutput{
+					Location: &client.ActionLocationShowOutput{
+						Environment: &client.ActionEnvironmentShowOutput{Id: 1},
+					},
+				},
+			})
+		case "/v7.0/environments/1/dataset_plans":
+			writeAPIResponse(t, w, "dataset_plans", testEnvironmentDatasetPlans())
+		case "/v7.0/datasets/42/plans":
+			if r.Method == http.MethodPost {
+				createBody = readRequestBody(t, r)
+				writeAPIResponse(t, w, "plan", &client.ActionDatasetPlanCreateOutput{Id: 3})
+				return
+			}
+
+			writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{
+				{
+					Id: 3,
+					EnvironmentDatasetPlan: &client.ActionEnvironmentDatasetPlanShowOutput{
+						DatasetPlan: &client.ActionDatasetPlanShowOutput{
+							Name:  "daily_backup",
+							Label: "Daily backup",
+						},
+					},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetPlan().Schema, map[string]interface{}{
+		"vps":  123,
+		"plan": "daily_backup",
+	})
+
+	if err := resourceDatasetPlanCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(createBody, `"environment_dataset_plan":11`) {
+		t.Fatalf("create body = %s", createBody)
+	}
+	if d.Id() != "3" {
+		t.Fatalf("id = %q, want 3", d.Id())
+	}
+	assertResourceValue(t, d, "dataset", 42)
+	assertResourceValue(t, d, "label", "Daily backup")
+}
+
+func TestResourceDatasetPlanCreateRejectsPlanNotAddableByUser(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/environments":
+			writeAPIResponse(t, w, "environments", []*client.ActionEnvironmentListOutput{
+				{Id: 1, Label: "Production"},
+			})
+		case "/v7.0/environments/1/dataset_plans":
+			writeAPIResponse(t, w, "dataset_plans", testEnvironmentDatasetPlans())
+		default:
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetPlan().Schema, map[string]interface{}{
+		"dataset":     77,
+		"environment": "Production",
+		"plan":        "hourly_backup",
+	})
+
+	err := resourceDatasetPlanCreate(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "cannot be enabled by users") {
+		t.Fatalf("err = %v, want user add error", err)
+	}
+}
+
+func TestResourceDatasetPlanReadRemovesDisabledPlan(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "plans", []*client.ActionDatasetPlanIndexOutput{})
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceDatasetPlan().Schema,
+		"3",
+		map[string]string{
+			"dataset": "42",
+		},
+		nil,
+	)
+
+	if err := resourceDatasetPlanRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
@@ -0,0 +1,184 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"time"
+)
+
+func resourceDatasetRollback() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatasetRollbackCreate,
+		Read:   resourceDatasetRollbackRead,
+		Delete: resourceDatasetRollbackDelete,
+
+		Description: `
+Rolls back a dataset to a snapshot when created. Datasets of a running VPS
+are rolled back while the VPS is stopped, the VPS is started again afterwards.
+Changing any argument performs another rollback, destroying the resource only
+removes it from the state. The resource is removed from the state also when
+the dataset no longer exists.
+
+Other resources are not refreshed by the rollback. Data sources reading
+the rolled back dataset, e.g. *vpsadmin_dataset_snapshots*, should list this
+resource in *depends_on*, so that they are read after the rollback. Resources
+*vpsadmin_dataset* and *vpsadmin_dataset_snapshot* of snapshots destroyed by
+the rollback are updated on the next refresh, run
+` + "`terraform apply -refresh-only`" + ` to update them right away.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"dataset": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Dataset ID, e.g. from vpsadmin_dataset",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"vps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "VPS ID, rolls back the VPS root dataset",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"snapshot": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Snapshot ID, e.g. from vpsadmin_dataset_snapshot",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"triggers": &schema.Schema{
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values which cause another rollback when changed",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"snapshot_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the snapshot the dataset was rolled back to",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceDatasetRollbackCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	var datasetId, vpsId int64
+
+	if v, ok := d.GetOk("vps"); ok {
+		id, err := getVpsRootDatasetId(api, v.(int))
+		if err != nil {
+			return err
+		}
+
+		datasetId = id
+		vpsId = int64(v.(int))
+	} else {
+		datasetId = int64(d.Get("dataset").(int))
+
+		ds, err := datasetShow(api, int(datasetId))
+		if err != nil {
+			return err
+		}
+
+		if ds.Vps != nil {
+			vpsId = ds.Vps.Id
+		}
+	}
+
+	snapshotId := int64(d.Get("snapshot").(int))
+
+	snap, err := findDatasetSnapshot(api, datasetId, snapshotId)
+	if err != nil {
+		return err
+	} else if snap == nil {
+		return fmt.Errorf("Snapshot %d not found in dataset %d", snapshotId, datasetId)
+	}
+
+	restart := false
+
+	if vpsId != 0 {
+		vps, err := vpsShow(api, int(vpsId))
+		if err != nil {
+			return err
+		}
+
+		if vps.IsRunning {
+			if err := stopVps(api, vpsId); err != nil {
+				return err
+			}
+
+			restart = true
+		}
+	}
+
+	if err := rollbackDatasetSnapshot(api, datasetId, snapshotId); err != nil {
+		// Do not leave the VPS stopped when the rollback fails
+		if restart {
+			if startErr := startVps(api, vpsId); startErr != nil {
+				return fmt.Errorf("%v, VPS %d could not be started again: %v", err, vpsId, startErr)
+			}
+		}
+
+		return err
+	}
+
+	if restart {
+		if err := startVps(api, vpsId); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(fmt.Sprintf(
+		"%d/%d/%s",
+		datasetId,
+		snapshotId,
+		time.Now().UTC().Format(time.RFC3339),
+	))
+	d.Set("dataset", datasetId)
+	d.Set("snapshot_name", snap.Name)
+
+	if vpsId != 0 {
+		d.Set("vps", vpsId)
+	}
+
+	return resourceDatasetRollbackRead(d, m)
+}
+
+func resourceDatasetRollbackRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	datasetId := d.Get("dataset").(int)
+
+	show := api.Dataset.Show.Prepare()
+	show.SetPathParamInt("dataset_id", int64(datasetId))
+
+	resp, err := show.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		log.Printf(
+			"[INFO] Dataset %d not found (%s), removing rollback from state",
+			datasetId, resp.Message,
+		)
+		d.SetId("")
+		return nil
+	}
+
+	// The rollback has already happened, there is nothing else to refresh
+	return nil
+}
+
+func resourceDatasetRollbackDelete(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[INFO] Removing rollback of dataset %d from state", d.Get("dataset").(int))
+	d.SetId("")
+	return nil
+}
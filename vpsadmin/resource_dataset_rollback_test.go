@@ -0,0 +1,188 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceDatasetRollbackRestartsRunningVps(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:        123,
+				IsRunning: true,
+				Dataset:   &client.ActionDatasetShowOutput{Id: 42},
+			})
+		case "/v7.0/datasets/42":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 42})
+		case "/v7.0/datasets/42/snapshots":
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: 5, Name: "2026-10-19T10:00:00"},
+			})
+		case "/v7.0/vpses/123/stop":
+			calls = append(calls, "stop")
+			writeAPIResponse(t, w, "vps", map[string]interface{}{})
+		case "/v7.0/datasets/42/snapshots/5/rollback":
+			calls = append(calls, "rollback")
+			writeAPIResponse(t, w, "snapshot", map[string]interface{}{})
+		case "/v7.0/vpses/123/start":
+			calls = append(calls, "start")
+			writeAPIResponse(t, w, "vps", map[string]interface{}{})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetRollback().Schema, map[string]interface{}{
+		"vps":      123,
+		"snapshot": 5,
+	})
+
+	if err := resourceDatasetRollbackCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "stop,rollback,start" {
+		t.Fatalf("calls = %v, want [stop rollback start]", calls)
+	}
+	assertResourceValue(t, d, "dataset", 42)
+	assertResourceValue(t, d, "snapshot_name", "2026-10-19T10:00:00")
+}
+
+func TestResourceDatasetRollbackRestartsVpsOnFailure(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:        123,
+				IsRunning: true,
+				Dataset:   &client.ActionDatasetShowOutput{Id: 42},
+			})
+		case "/v7.0/datasets/42/snapshots":
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: 5, Name: "2026-10-19T10:00:00"},
+			})
+		case "/v7.0/vpses/123/stop":
+			calls = append(calls, "stop")
+			writeAPIResponse(t, w, "vps", map[string]interface{}{})
+		case "/v7.0/datasets/42/snapshots/5/rollback":
+			calls = append(calls, "rollback")
+			writeAPIError(t, w, "rollback failed")
+		case "/v7.0/vpses/123/start":
+			calls = append(calls, "start")
+			writeAPIResponse(t, w, "vps", map[string]interface{}{})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetRollback().Schema, map[string]interface{}{
+		"vps":      123,
+		"snapshot": 5,
+	})
+
+	err := resourceDatasetRollbackCreate(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "rollback failed") {
+		t.Fatalf("err = %v, want rollback error", err)
+	}
+
+	if strings.Join(calls, ",") != "stop,rollback,start" {
+		t.Fatalf("calls = %v, want [stop rollback start]", calls)
+	}
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
+
+func TestResourceDatasetRollbackOfNasDataset(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 77, Name: "nas/db"})
+		case "/v7.0/datasets/77/snapshots":
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: 5, Name: "2026-10-19T10:00:00"},
+			})
+		case "/v7.0/datasets/77/snapshots/5/rollback":
+			calls = append(calls, "rollback")
+			writeAPIResponse(t, w, "snapshot", map[string]interface{}{})
+		default:
+			calls = append(calls, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetRollback().Schema, map[string]interface{}{
+		"dataset":  77,
+		"snapshot": 5,
+	})
+
+	if err := resourceDatasetRollbackCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "rollback" {
+		t.Fatalf("calls = %v, want [rollback]", calls)
+	}
+	if !strings.HasPrefix(d.Id(), "77/5/") {
+		t.Fatalf("id = %q, want prefix 77/5/", d.Id())
+	}
+}
+
+func TestResourceDatasetRollbackReadRemovesMissingDataset(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/datasets/77" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		writeAPIError(t, w, "object not found")
+	})
+
+	d := newResourceDataWithDiff(t, resourceDatasetRollback().Schema, "77/5/2026-10-19T10:00:00Z", map[string]string{
+		"dataset":  "77",
+		"snapshot": "5",
+	}, nil)
+
+	if err := resourceDatasetRollbackRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
+
+func TestResourceDatasetRollbackRequiresSnapshotOfDataset(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/datasets/77":
+			writeAPIResponse(t, w, "dataset", &client.ActionDatasetShowOutput{Id: 77})
+		case "/v7.0/datasets/77/snapshots":
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{})
+		default:
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetRollback().Schema, map[string]interface{}{
+		"dataset":  77,
+		"snapshot": 5,
+	})
+
+	err := resourceDatasetRollbackCreate(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "Snapshot 5 not found in dataset 77") {
+		t.Fatalf("err = %v, want snapshot not found", err)
+	}
+}
@@ -0,0 +1,192 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"strconv"
+	"strings"
+)
+
+func resourceDatasetSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatasetSnapshotCreate,
+		Read:   resourceDatasetSnapshotRead,
+		Delete: resourceDatasetSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDatasetSnapshotImport,
+		},
+
+		Description: `
+Creates a snapshot of a dataset, e.g. before risky changes. The snapshot is
+deleted when the resource is destroyed. Changing any argument creates a new
+snapshot.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"dataset": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Dataset ID, e.g. from vpsadmin_dataset",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"vps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "VPS ID, snapshots the VPS root dataset",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "vps"},
+			},
+			"label": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Snapshot label",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Snapshot name",
+				Computed:    true,
+			},
+			"created_at": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Time of creation",
+				Computed:    true,
+			},
+			"history_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "History identifier of the dataset at the time of the snapshot",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceDatasetSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	datasetId := d.Get("dataset").(int)
+
+	if v, ok := d.GetOk("vps"); ok {
+		id, err := getVpsRootDatasetId(api, v.(int))
+		if err != nil {
+			return err
+		}
+
+		datasetId = int(id)
+		d.Set("dataset", datasetId)
+	}
+
+	create := api.Dataset.Snapshot.Create.Prepare()
+	create.SetPathParamInt("dataset_id", int64(datasetId))
+
+	input := create.NewInput()
+
+	if v, ok := d.GetOk("label"); ok {
+		input.SetLabel(v.(string))
+	}
+
+	log.Printf("[INFO] Creating snapshot of dataset %d", datasetId)
+
+	resp, err := create.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Snapshot creation failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Snapshot creation failed: %v", err)
+	}
+
+	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
+
+	return resourceDatasetSnapshotRead(d, m)
+}
+
+func resourceDatasetSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot id: %v", err)
+	}
+
+	snap, err := findDatasetSnapshot(api, int64(d.Get("dataset").(int)), id)
+	if err != nil {
+		return err
+	}
+
+	if snap == nil {
+		log.Printf("[INFO] Snapshot %d not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("label", snap.Label)
+	d.Set("name", snap.Name)
+	d.Set("created_at", snap.CreatedAt)
+	d.Set("history_id", snap.HistoryId)
+
+	return nil
+}
+
+func resourceDatasetSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot id: %v", err)
+	}
+
+	log.Printf("[INFO] Deleting snapshot: %s", d.Id())
+
+	del := api.Dataset.Snapshot.Delete.Prepare()
+	del.SetPathParamInt("dataset_id", int64(d.Get("dataset").(int)))
+	del.SetPathParamInt("snapshot_id", id)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Snapshot deletion failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Snapshot deletion failed: %v", err)
+	}
+
+	return nil
+}
+
+func resourceDatasetSnapshotImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid snapshot import ID '%s', expected dataset_id/snapshot_id", d.Id())
+	}
+
+	datasetId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid dataset id in '%s': %v", d.Id(), err)
+	}
+
+	d.SetId(parts[1])
+	d.Set("dataset", datasetId)
+
+	if err := resourceDatasetSnapshotRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid snapshot id: %v", err)
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("Snapshot %s not found in dataset %d", parts[1], datasetId)
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
@@ -0,0 +1,178 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceDatasetSnapshotCreate(t *testing.T) {
+	var createBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/datasets/77/snapshots" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			createBody = readRequestBody(t, r)
+			writeAPIResponse(t, w, "snapshot", &client.ActionDatasetSnapshotCreateOutput{Id: 5})
+			return
+		}
+
+		writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+			{Id: 4, Name: "2026-10-18T10:00:00"},
+			{
+				Id:        5,
+				Name:      "2026-10-19T10:00:00",
+				Label:     "before-upgrade",
+				CreatedAt: "2026-10-19T10:00:00Z",
+				HistoryId: 3,
+			},
+		})
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetSnapshot().Schema, map[string]interface{}{
+		"dataset": 77,
+		"label":   "before-upgrade",
+	})
+
+	if err := resourceDatasetSnapshotCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(createBody, `"label":"before-upgrade"`) {
+		t.Fatalf("create body = %s", createBody)
+	}
+	if d.Id() != "5" {
+		t.Fatalf("id = %q, want 5", d.Id())
+	}
+	assertResourceValue(t, d, "name", "2026-10-19T10:00:00")
+	assertResourceValue(t, d, "created_at", "2026-10-19T10:00:00Z")
+	assertResourceValue(t, d, "history_id", 3)
+}
+
+func TestResourceDatasetSnapshotReadRemovesMissingSnapshot(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+			{Id: 4, Name: "2026-10-18T10:00:00"},
+		})
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceDatasetSnapshot().Schema,
+		"5",
+		map[string]string{
+			"dataset": "77",
+		},
+		nil,
+	)
+
+	if err := resourceDatasetSnapshotRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
+
+func TestResourceDatasetSnapshotImport(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/datasets/77/snapshots" {
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+			{Id: 5, Name: "2026-10-19T10:00:00"},
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceDatasetSnapshot().Schema, "77/5", nil, nil)
+
+	if _, err := resourceDatasetSnapshotImport(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "5" {
+		t.Fatalf("id = %q, want 5", d.Id())
+	}
+	assertResourceValue(t, d, "dataset", 77)
+	assertResourceValue(t, d, "name", "2026-10-19T10:00:00")
+}
+
+func TestResourceDatasetSnapshotCreateOfVpsRootDataset(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:      123,
+				Dataset: &client.ActionDatasetShowOutput{Id: 42},
+			})
+		case "/v7.0/datasets/42/snapshots":
+			if r.Method == http.MethodPost {
+				writeAPIResponse(t, w, "snapshot", &client.ActionDatasetSnapshotCreateOutput{Id: 5})
+				return
+			}
+
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: 5, Name: "2026-10-19T10:00:00"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDatasetSnapshot().Schema, map[string]interface{}{
+		"vps": 123,
+	})
+
+	if err := resourceDatasetSnapshotCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "dataset", 42)
+	assertResourceValue(t, d, "name", "2026-10-19T10:00:00")
+}
+
+func TestDataSourceDatasetSnapshotsOfVpsFiltersByLabel(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:      123,
+				Dataset: &client.ActionDatasetShowOutput{Id: 42},
+			})
+		case "/v7.0/datasets/42/snapshots":
+			writeAPIResponse(t, w, "snapshots", []*client.ActionDatasetSnapshotIndexOutput{
+				{Id: 6, Name: "2026-10-19T12:00:00", Label: "nightly", CreatedAt: "2026-10-19T12:00:00Z", HistoryId: 2},
+				{Id: 4, Name: "2026-10-18T01:00:00", CreatedAt: "2026-10-18T01:00:00Z"},
+				{Id: 5, Name: "2026-10-19T01:00:00", Label: "nightly", CreatedAt: "2026-10-19T01:00:00Z", HistoryId: 2},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceDatasetSnapshots().Schema, map[string]interface{}{
+		"vps":   123,
+		"label": "nightly",
+	})
+
+	if err := dataSourceDatasetSnapshotsRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "dataset", 42)
+	assertResourceValue(t, d, "snapshots.#", 2)
+	assertResourceValue(t, d, "snapshots.0.id", 5)
+	assertResourceValue(t, d, "snapshots.1.id", 6)
+	assertResourceValue(t, d, "snapshots.0.created_at", "2026-10-19T01:00:00Z")
+	assertResourceValue(t, d, "snapshots.0.history_id", 2)
+}
@@ -0,0 +1,310 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"strconv"
+	"strings"
+)
+
+func resourceExportHost() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceExportHostCreate,
+		Read:   resourceExportHostRead,
+		Update: resourceExportHostUpdate,
+		Delete: resourceExportHostDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceExportHostImport,
+		},
+
+		Description: `
+Allows an IP address to mount an NFS export of a dataset. Hosts can override
+export options. To allow only listed hosts, set *export_all_vps* of
+*vpsadmin_dataset* to false.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"export": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Export ID, see export_id of vpsadmin_dataset",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"ip_address": &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "IP address allowed to mount the export",
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentIpAddress,
+				ValidateFunc:     validation.IsIPAddress,
+			},
+			"read_write": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Read-write access, defaults to the export setting",
+				Optional:    true,
+				Computed:    true,
+			},
+			"sync": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Server will reply only after changes were committed, defaults to the export setting",
+				Optional:    true,
+				Computed:    true,
+			},
+			"subtree_check": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Enable subtree checking, defaults to the export setting",
+				Optional:    true,
+				Computed:    true,
+			},
+			"root_squash": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Enable root squash, defaults to the export setting",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceExportHostCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	ip, err := findIpAddressByAddr(api, d.Get("ip_address").(string))
+	if err != nil {
+		return err
+	}
+
+	create := api.Export.Host.Create.Prepare()
+	create.SetPathParamInt("export_id", int64(d.Get("export").(int)))
+
+	input := create.NewInput()
+	input.SetIpAddress(ip.Id)
+
+	if v, ok := d.GetOkExists("read_write"); ok {
+		input.SetRw(v.(bool))
+	}
+
+	if v, ok := d.GetOkExists("sync"); ok {
+		input.SetSync(v.(bool))
+	}
+
+	if v, ok := d.GetOkExists("subtree_check"); ok {
+		input.SetSubtreeCheck(v.(bool))
+	}
+
+	if v, ok := d.GetOkExists("root_squash"); ok {
+		input.SetRootSquash(v.(bool))
+	}
+
+	log.Printf("[INFO] Adding host %s to export %d", ip.Addr, d.Get("export").(int))
+
+	resp, err := create.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Export host creation failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Export host creation failed: %v", err)
+	}
+
+	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
+
+	return resourceExportHostRead(d, m)
+}
+
+func resourceExportHostRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid export host id: %v", err)
+	}
+
+	host, err := findExportHost(api, int64(d.Get("export").(int)), id)
+	if err != nil {
+		return err
+	}
+
+	if host == nil {
+		log.Printf("[INFO] Export host %d not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	if host.IpAddress != nil {
+		d.Set("ip_address", host.IpAddress.Addr)
+	}
+
+	d.Set("read_write", host.Rw)
+	d.Set("sync", host.Sync)
+	d.Set("subtree_check", host.SubtreeCheck)
+	d.Set("root_squash", host.RootSquash)
+
+	return nil
+}
+
+func resourceExportHostUpdate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid export host id: %v", err)
+	}
+
+	update := api.Export.Host.Update.Prepare()
+	update.SetPathParamInt("export_id", int64(d.Get("export").(int)))
+	update.SetPathParamInt("host_id", id)
+
+	input := update.NewInput()
+
+	if d.HasChange("read_write") {
+		input.SetRw(d.Get("read_write").(bool))
+	}
+
+	if d.HasChange("sync") {
+		input.SetSync(d.Get("sync").(bool))
+	}
+
+	if d.HasChange("subtree_check") {
+		input.SetSubtreeCheck(d.Get("subtree_check").(bool))
+	}
+
+	if d.HasChange("root_squash") {
+		input.SetRootSquash(d.Get("root_squash").(bool))
+	}
+
+	if input.AnySelected() {
+		resp, err := update.Call()
+
+		if err != nil {
+			return err
+		} else if !resp.Status {
+			return fmt.Errorf("Export host update failed: %s", resp.Message)
+		}
+
+		if err := waitForOperation(resp); err != nil {
+			return fmt.Errorf("Export host update failed: %v", err)
+		}
+	}
+
+	return resourceExportHostRead(d, m)
+}
+
+func resourceExportHostDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid export host id: %v", err)
+	}
+
+	log.Printf("[INFO] Deleting export host: %s", d.Id())
+
+	del := api.Export.Host.Delete.Prepare()
+	del.SetPathParamInt("export_id", int64(d.Get("export").(int)))
+	del.SetPathParamInt("host_id", id)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Export host deletion failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Export host deletion failed: %v", err)
+	}
+
+	return nil
+}
+
+func resourceExportHostImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	api := m.(*Config).getClient()
+
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid export host import ID '%s', expected export_id/ip_address", d.Id())
+	}
+
+	exportId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid export id in '%s': %v", d.Id(), err)
+	}
+
+	host, err := exportHostFindByAddr(api, int64(exportId), parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(strconv.FormatInt(host.Id, 10))
+	d.Set("export", exportId)
+
+	if err := resourceExportHostRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid export host id: %v", err)
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
+
+func exportHostList(api *client.Client, exportId int64) ([]*client.ActionExportHostIndexOutput, error) {
+	list := api.Export.Host.Index.Prepare()
+	list.SetPathParamInt("export_id", exportId)
+	list.SetMetaInput(&client.ActionExportHostIndexMetaGlobalInput{
+		Includes: "ip_address",
+	})
+	list.MetaInput.SelectParameters("Includes")
+
+	input := list.NewInput()
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		return nil, fmt.Errorf("Failed to list export hosts: %s", resp.Message)
+	}
+
+	return resp.Output, nil
+}
+
+// findExportHost returns nil if the host is not found in the export
+func findExportHost(api *client.Client, exportId, hostId int64) (*client.ActionExportHostIndexOutput, error) {
+	hosts, err := exportHostList(api, exportId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		if host.Id == hostId {
+			return host, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func exportHostFindByAddr(api *client.Client, exportId int64, addr string) (*client.ActionExportHostIndexOutput, error) {
+	hosts, err := exportHostList(api, exportId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		if host.IpAddress != nil && canonicalIpAddress(host.IpAddress.Addr) == canonicalIpAddress(addr) {
+			return host, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Host %s not found in export %d", addr, exportId)
+}
@@ -0,0 +1,118 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceExportHostCreate(t *testing.T) {
+	var createBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/ip_addresses":
+			assertQueryValue(t, r, "ip_address[addr]", "198.51.100.10")
+			writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+				{Id: 9, Addr: "198.51.100.10"},
+			})
+		case "/v7.0/exports/88/hosts":
+			if r.Method == http.MethodPost {
+				createBody = readRequestBody(t, r)
+				writeAPIResponse(t, w, "host", &client.ActionExportHostCreateOutput{Id: 5})
+				return
+			}
+
+			writeAPIResponse(t, w, "hosts", []*client.ActionExportHostIndexOutput{
+				{
+					Id:        5,
+					IpAddress: &client.ActionIpAddressShowOutput{Id: 9, Addr: "198.51.100.10"},
+					Rw:        false,
+					Sync:      true,
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceExportHost().Schema, map[string]interface{}{
+		"export":     88,
+		"ip_address": "198.51.100.10",
+		"read_write": false,
+	})
+
+	if err := resourceExportHostCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(createBody, `"ip_address":9`) || !strings.Contains(createBody, `"rw":false`) {
+		t.Fatalf("create body = %s", createBody)
+	}
+	if d.Id() != "5" {
+		t.Fatalf("id = %q, want 5", d.Id())
+	}
+	assertResourceValue(t, d, "read_write", false)
+	assertResourceValue(t, d, "sync", true)
+}
+
+func TestResourceExportHostImportByAddress(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/exports/88/hosts":
+			writeAPIResponse(t, w, "hosts", []*client.ActionExportHostIndexOutput{
+				{Id: 4, IpAddress: &client.ActionIpAddressShowOutput{Addr: "198.51.100.11"}},
+				{Id: 5, IpAddress: &client.ActionIpAddressShowOutput{Addr: "198.51.100.10"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(t, resourceExportHost().Schema, "88/198.51.100.10", nil, nil)
+
+	if _, err := resourceExportHostImport(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "5" {
+		t.Fatalf("id = %q, want 5", d.Id())
+	}
+	assertResourceValue(t, d, "export", 88)
+	assertResourceValue(t, d, "ip_address", "198.51.100.10")
+}
+
+func TestResourceExportHostReadRemovesMissingHost(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/exports/88/hosts" {
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "hosts", []*client.ActionExportHostIndexOutput{
+			{Id: 4, IpAddress: &client.ActionIpAddressShowOutput{Addr: "198.51.100.11"}},
+		})
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceExportHost().Schema,
+		"5",
+		map[string]string{
+			"export": "88",
+		},
+		nil,
+	)
+
+	if err := resourceExportHostRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
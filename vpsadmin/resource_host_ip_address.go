@@ -0,0 +1,221 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+)
+
+func resourceHostIpAddress() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceHostIpAddressCreate,
+		Read:   resourceHostIpAddressRead,
+		Delete: resourceHostIpAddressDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceHostIpAddressImport,
+		},
+
+		Description: `
+Assigns a host address from a routed IP address to the network interface
+the routed address is assigned to. Useful to pick specific addresses from
+routed IPv6 networks. Host addresses which do not exist in vpsAdmin yet are
+created and removed again on destroy, existing or imported host addresses
+are only unassigned.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"ip_address": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the routed IP address, e.g. from vpsadmin_ip_address",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"address": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Host address, must be within the routed IP address",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPAddress,
+				// vpsAdmin returns addresses in canonical form
+				DiffSuppressFunc: suppressEquivalentIpAddress,
+			},
+			"routed_address": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Routed IP address the host address belongs to, in CIDR notation",
+				Computed:    true,
+			},
+			"network": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Network the address belongs to, in CIDR notation",
+				Computed:    true,
+			},
+			"network_interface": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the network interface the address is assigned to",
+				Computed:    true,
+			},
+			"vps": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the VPS the address is assigned to",
+				Computed:    true,
+			},
+			"delete_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "True if the host address was created by this resource and is deleted on destroy",
+				Computed:    true,
+			},
+			"reverse_record": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Reverse DNS (PTR) record of the host address, see vpsadmin_host_ip_reverse_record",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceHostIpAddressCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	ipId := int64(d.Get("ip_address").(int))
+	addr := d.Get("address").(string)
+
+	ip, err := ipAddressShow(api, ipId)
+	if err != nil {
+		return err
+	}
+
+	if err := checkHostIpAddressInRoutedAddress(ip, addr); err != nil {
+		return err
+	}
+
+	if ip.NetworkInterface == nil {
+		return fmt.Errorf("IP address %s/%d is not assigned to any network interface", ip.Addr, ip.Prefix)
+	}
+
+	var hostAddrId int64
+
+	hostAddr, err := findRoutedHostIpAddress(api, ipId, addr)
+	if err != nil {
+		return err
+	}
+
+	deleteOnDestroy := hostAddr == nil
+
+	if hostAddr == nil {
+		created, err := createHostIpAddress(api, ipId, addr)
+		if err != nil {
+			return err
+		}
+
+		hostAddrId = created.Id
+	} else if hostAddr.Assigned {
+		return fmt.Errorf("Host IP address %s is already assigned", addr)
+	} else {
+		hostAddrId = hostAddr.Id
+	}
+
+	if err := assignHostIpAddress(api, hostAddrId); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(hostAddrId, 10))
+	d.Set("delete_on_destroy", deleteOnDestroy)
+
+	return resourceHostIpAddressRead(d, m)
+}
+
+func resourceHostIpAddressRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid host IP address id: %v", err)
+	}
+
+	hostAddr, err := hostIpAddressShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if !hostAddr.Assigned {
+		log.Printf("[INFO] Host IP address %s is not assigned, removing from state", hostAddr.Addr)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("address", hostAddr.Addr)
+	d.Set("reverse_record", hostAddr.ReverseRecordValue)
+
+	if ip := hostAddr.IpAddress; ip != nil {
+		d.Set("ip_address", ip.Id)
+		d.Set("routed_address", fmt.Sprintf("%s/%d", ip.Addr, ip.Prefix))
+
+		if ip.Network != nil {
+			d.Set("network", fmt.Sprintf("%s/%d", ip.Network.Address, ip.Network.Prefix))
+		}
+
+		if ip.NetworkInterface != nil {
+			d.Set("network_interface", ip.NetworkInterface.Name)
+
+			if ip.NetworkInterface.Vps != nil {
+				d.Set("vps", ip.NetworkInterface.Vps.Id)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceHostIpAddressDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid host IP address id: %v", err)
+	}
+
+	hostAddr, err := hostIpAddressShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if hostAddr.Assigned {
+		if err := freeHostIpAddress(api, id); err != nil {
+			return err
+		}
+	}
+
+	if hostAddr.UserCreated && d.Get("delete_on_destroy").(bool) {
+		return deleteHostIpAddress(api, id)
+	}
+
+	return nil
+}
+
+func resourceHostIpAddressImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		api := m.(*Config).getClient()
+
+		hostAddr, err := findHostIpAddressByAddr(api, d.Id())
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(hostAddr.Id, 10))
+	}
+
+	if err := resourceHostIpAddressRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid host IP address id: %v", err)
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("Host IP address is not assigned")
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
@@ -0,0 +1,176 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestCheckHostIpAddressInRoutedAddress(t *testing.T) {
+	t.Parallel()
+
+	ip := &client.ActionIpAddressShowOutput{Addr: "2001:db8:1::", Prefix: 64}
+
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: "2001:db8:1::25"},
+		{addr: "2001:db8:1:0:ffff::1"},
+		{addr: "2001:db8:2::25", wantErr: true},
+		{addr: "198.51.100.10", wantErr: true},
+		{addr: "mail", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.addr, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkHostIpAddressInRoutedAddress(ip, tt.addr)
+			if tt.wantErr && err == nil {
+				t.Fatalf("checkHostIpAddressInRoutedAddress(%q) error = nil, want error", tt.addr)
+			} else if !tt.wantErr && err != nil {
+				t.Fatalf("checkHostIpAddressInRoutedAddress(%q) error = %v", tt.addr, err)
+			}
+		})
+	}
+}
+
+func TestResourceHostIpAddressCreateCreatesMissingAddress(t *testing.T) {
+	var created, assigned bool
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/ip_addresses/9":
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+				Id:               9,
+				Addr:             "2001:db8:1::",
+				Prefix:           64,
+				NetworkInterface: &client.ActionNetworkInterfaceShowOutput{Name: "venet0"},
+			})
+		case "/v7.0/host_ip_addresses":
+			if r.Method == http.MethodPost {
+				created = true
+				writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressCreateOutput{Id: 42})
+				return
+			}
+
+			assertQueryValue(t, r, "host_ip_address[ip_address]", "9")
+			assertQueryValue(t, r, "host_ip_address[addr]", "2001:db8:1::25")
+			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{})
+		case "/v7.0/host_ip_addresses/42/assign":
+			assigned = true
+			writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressAssignOutput{})
+		case "/v7.0/host_ip_addresses/42":
+			writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressShowOutput{
+				Id:                 42,
+				Addr:               "2001:db8:1::25",
+				Assigned:           true,
+				ReverseRecordValue: "mail.example.com.",
+				IpAddress: &client.ActionIpAddressShowOutput{
+					Id:      9,
+					Addr:    "2001:db8:1::",
+					Prefix:  64,
+					Network: &client.ActionNetworkShowOutput{Address: "2001:db8::", Prefix: 32},
+					NetworkInterface: &client.ActionNetworkInterfaceShowOutput{
+						Name: "venet0",
+						Vps:  &client.ActionVpsShowOutput{Id: 123},
+					},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceHostIpAddress().Schema, map[string]interface{}{
+		"ip_address": 9,
+		"address":    "2001:db8:1::25",
+	})
+
+	if err := resourceHostIpAddressCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !created || !assigned {
+		t.Fatalf("created = %v, assigned = %v, want both true", created, assigned)
+	}
+	assertResourceValue(t, d, "routed_address", "2001:db8:1::/64")
+	assertResourceValue(t, d, "network", "2001:db8::/32")
+	assertResourceValue(t, d, "network_interface", "venet0")
+	assertResourceValue(t, d, "vps", 123)
+	assertResourceValue(t, d, "reverse_record", "mail.example.com.")
+	assertResourceValue(t, d, "delete_on_destroy", true)
+}
+
+func TestResourceHostIpAddressCreateRejectsAddressOutsideRoutedAddress(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/ip_addresses/9" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+			Id:     9,
+			Addr:   "2001:db8:1::",
+			Prefix: 64,
+		})
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceHostIpAddress().Schema, map[string]interface{}{
+		"ip_address": 9,
+		"address":    "2001:db8:2::25",
+	})
+
+	if err := resourceHostIpAddressCreate(d, cfg); err == nil {
+		t.Fatal("resourceHostIpAddressCreate() error = nil, want error")
+	}
+}
+
+func TestResourceHostIpAddressDeleteKeepsAdoptedAddress(t *testing.T) {
+	var freed bool
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v7.0/host_ip_addresses/42/free":
+			freed = true
+			writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressFreeOutput{})
+		case r.URL.Path == "/v7.0/host_ip_addresses/42" && r.Method == http.MethodDelete:
+			t.Errorf("adopted host address must not be deleted")
+			writeAPIResponse(t, w, "host_ip_address", map[string]interface{}{})
+		case r.URL.Path == "/v7.0/host_ip_addresses/42":
+			writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressShowOutput{
+				Id:          42,
+				Addr:        "2001:db8:1::25",
+				Assigned:    true,
+				UserCreated: true,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(t, resourceHostIpAddress().Schema, "42", nil, nil)
+
+	if err := resourceHostIpAddressDelete(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !freed {
+		t.Fatal("host address was not freed")
+	}
+}
+
+func TestSuppressEquivalentIpAddress(t *testing.T) {
+	if !suppressEquivalentIpAddress("address", "2001:db8:1::25", "2001:0db8:0001:0000::0025", nil) {
+		t.Fatal("equivalent IPv6 addresses are not suppressed")
+	}
+
+	if suppressEquivalentIpAddress("address", "2001:db8:1::25", "2001:db8:1::26", nil) {
+		t.Fatal("different addresses are suppressed")
+	}
+}
@@ -0,0 +1,139 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+)
+
+func resourceHostIpReverseRecord() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceHostIpReverseRecordCreate,
+		Read:   resourceHostIpReverseRecordRead,
+		Update: resourceHostIpReverseRecordUpdate,
+		Delete: resourceHostIpReverseRecordDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceHostIpReverseRecordImport,
+		},
+
+		Description: `
+Manages the reverse DNS (PTR) record of a host IP address. The record is
+cleared when the resource is destroyed.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"host_ip_address": &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "Host IP address, e.g. from ipv4_addresses of vpsadmin_vps",
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentIpAddress,
+				ValidateFunc:     validation.IsIPAddress,
+			},
+			"reverse_record": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Reverse record value, e.g. mail.example.com.",
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+		},
+	}
+}
+
+func resourceHostIpReverseRecordCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	hostAddr, err := findHostIpAddressByAddr(api, d.Get("host_ip_address").(string))
+	if err != nil {
+		return err
+	}
+
+	if err := setHostIpReverseRecord(api, hostAddr.Id, d.Get("reverse_record").(string)); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(hostAddr.Id, 10))
+
+	return resourceHostIpReverseRecordRead(d, m)
+}
+
+func resourceHostIpReverseRecordRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid host IP address id: %v", err)
+	}
+
+	hostAddr, err := hostIpAddressShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if hostAddr.ReverseRecordValue == "" {
+		log.Printf("[INFO] Host IP address %s has no reverse record, removing from state", hostAddr.Addr)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("host_ip_address", hostAddr.Addr)
+	d.Set("reverse_record", hostAddr.ReverseRecordValue)
+
+	return nil
+}
+
+func resourceHostIpReverseRecordUpdate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid host IP address id: %v", err)
+	}
+
+	if d.HasChange("reverse_record") {
+		if err := setHostIpReverseRecord(api, id, d.Get("reverse_record").(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceHostIpReverseRecordRead(d, m)
+}
+
+func resourceHostIpReverseRecordDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid host IP address id: %v", err)
+	}
+
+	return setHostIpReverseRecord(api, id, "")
+}
+
+func resourceHostIpReverseRecordImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		api := m.(*Config).getClient()
+
+		hostAddr, err := findHostIpAddressByAddr(api, d.Id())
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(hostAddr.Id, 10))
+	}
+
+	if err := resourceHostIpReverseRecordRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid host IP address id: %v", err)
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("Host IP address has no reverse record")
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
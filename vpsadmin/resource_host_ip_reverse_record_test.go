@@ -0,0 +1,78 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceHostIpReverseRecordCreate(t *testing.T) {
+	var updated bool
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/host_ip_addresses":
+			assertQueryValue(t, r, "host_ip_address[addr]", "198.51.100.10")
+			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{
+				{Id: 42, Addr: "198.51.100.10"},
+			})
+		case "/v7.0/host_ip_addresses/42":
+			if r.Method == http.MethodPut {
+				body := readRequestBody(t, r)
+				if !strings.Contains(body, `"reverse_record_value":"mail.example.com."`) {
+					t.Errorf("update body = %s", body)
+				}
+				updated = true
+				writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressUpdateOutput{})
+				return
+			}
+
+			writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressShowOutput{
+				Id:                 42,
+				Addr:               "198.51.100.10",
+				ReverseRecordValue: "mail.example.com.",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceHostIpReverseRecord().Schema, map[string]interface{}{
+		"host_ip_address": "198.51.100.10",
+		"reverse_record":  "mail.example.com.",
+	})
+
+	if err := resourceHostIpReverseRecordCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !updated {
+		t.Fatal("reverse record was not updated")
+	}
+	if d.Id() != "42" {
+		t.Fatalf("id = %q, want 42", d.Id())
+	}
+	assertResourceValue(t, d, "reverse_record", "mail.example.com.")
+}
+
+func TestResourceHostIpReverseRecordReadRemovesEmptyRecord(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "host_ip_address", &client.ActionHostIpAddressShowOutput{
+			Id:   42,
+			Addr: "198.51.100.10",
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceHostIpReverseRecord().Schema, "42", nil, nil)
+
+	if err := resourceHostIpReverseRecordRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
@@ -0,0 +1,284 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+)
+
+func resourceIpAddress() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIpAddressCreate,
+		Read:   resourceIpAddressRead,
+		Update: resourceIpAddressUpdate,
+		Delete: resourceIpAddressDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceIpAddressImport,
+		},
+
+		Description: `
+Assigns an IP address to a VPS network interface. A free address is picked
+by *version*, *role* and *location*, or a specific *address* can be requested.
+Changing *vps* moves the address to another VPS.
+
+Addresses assigned by this resource are included in *public_ipv4_count*,
+*private_ipv4_count* and *public_ipv6_count* of *vpsadmin_vps*. Leave these
+counts unset when managing addresses with this resource, an explicitly set
+count would free the extra addresses.
+
+With *reserve*, the address is owned by the current user until the resource
+is destroyed, so it is not given to anyone else while being moved between VPS.
+To keep the address even when this resource is replaced, assign an address
+from *vpsadmin_ip_reservation* instead.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"vps": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the VPS the address is assigned to",
+				Required:    true,
+			},
+			"address": &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "Specific address to assign, an unassigned address without owner is picked if not set",
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentIpAddress,
+				ConflictsWith:    []string{"version", "role", "location"},
+			},
+			"version": &schema.Schema{
+				Type:          schema.TypeInt,
+				Description:   "IP version of the picked address, 4 or 6",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IntInSlice([]int{4, 6}),
+				ConflictsWith: []string{"address"},
+			},
+			"role": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Role of the picked address, public_access or private_access",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.StringInSlice([]string{"public_access", "private_access"}, false),
+				ConflictsWith: []string{"address"},
+			},
+			"location": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Location label to pick the address from, defaults to the VPS location",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"address"},
+			},
+			"reserve": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Own the address by the current user while the resource exists",
+				Optional:    true,
+				Default:     false,
+			},
+			"owned_by_user": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "True if the address is owned by a user",
+				Computed:    true,
+			},
+			"prefix": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Prefix of the routed address",
+				Computed:    true,
+			},
+			"network": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Network the address belongs to, in CIDR notation",
+				Computed:    true,
+			},
+			"network_interface": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the network interface the address is assigned to",
+				Computed:    true,
+			},
+			"reverse_record": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Reverse DNS (PTR) record of the address, see vpsadmin_host_ip_reverse_record",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceIpAddressCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	vps, err := vpsShow(api, d.Get("vps").(int))
+	if err != nil {
+		return err
+	}
+
+	ip, err := pickIpAddress(api, d, vps.Node.Location.Id)
+	if err != nil {
+		return err
+	}
+
+	netif, err := getVpsNetworkInterface(api, vps.Id, 0, "")
+	if err != nil {
+		return err
+	}
+
+	if d.Get("reserve").(bool) {
+		if err := reserveIpAddress(api, ip.Id); err != nil {
+			return err
+		}
+	}
+
+	if err := assignIpAddress(api, ip.Id, netif.Id); err != nil {
+		if d.Get("reserve").(bool) {
+			if releaseErr := setIpAddressOwner(api, ip.Id, 0); releaseErr != nil {
+				log.Printf("[WARN] Unable to release reservation of IP address %d: %v", ip.Id, releaseErr)
+			}
+		}
+
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(ip.Id, 10))
+
+	return resourceIpAddressRead(d, m)
+}
+
+func resourceIpAddressRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid IP address id: %v", err)
+	}
+
+	ip, err := ipAddressShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if ip.NetworkInterface == nil {
+		log.Printf("[INFO] IP address %s is not assigned, removing from state", ip.Addr)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("vps", ip.NetworkInterface.Vps.Id)
+	d.Set("network_interface", ip.NetworkInterface.Name)
+	d.Set("address", ip.Addr)
+	d.Set("prefix", ip.Prefix)
+	d.Set("owned_by_user", ip.User != nil)
+
+	hostAddr, err := findRoutedHostIpAddress(api, id, ip.Addr)
+	if err != nil {
+		return err
+	}
+
+	if hostAddr != nil {
+		d.Set("reverse_record", hostAddr.ReverseRecordValue)
+	} else {
+		d.Set("reverse_record", "")
+	}
+
+	if ip.Network != nil {
+		d.Set("version", ip.Network.IpVersion)
+		d.Set("role", ip.Network.Role)
+		d.Set("network", fmt.Sprintf("%s/%d", ip.Network.Address, ip.Network.Prefix))
+
+		// Networks can be available in multiple locations, keep the configured one
+		if _, ok := d.GetOk("location"); !ok && ip.Network.PrimaryLocation != nil {
+			d.Set("location", ip.Network.PrimaryLocation.Label)
+		}
+	}
+
+	return nil
+}
+
+func resourceIpAddressUpdate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid IP address id: %v", err)
+	}
+
+	if d.HasChange("reserve") && d.Get("reserve").(bool) {
+		if err := reserveIpAddress(api, id); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("vps") {
+		netif, err := getVpsNetworkInterface(api, int64(d.Get("vps").(int)), 0, "")
+		if err != nil {
+			return err
+		}
+
+		// The address is already unassigned if the original VPS was destroyed
+		if err := freeAssignedIpAddress(api, id); err != nil {
+			return err
+		}
+
+		if err := assignIpAddress(api, id, netif.Id); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("reserve") && !d.Get("reserve").(bool) {
+		if err := setIpAddressOwner(api, id, 0); err != nil {
+			return err
+		}
+	}
+
+	return resourceIpAddressRead(d, m)
+}
+
+func resourceIpAddressDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid IP address id: %v", err)
+	}
+
+	if err := freeAssignedIpAddress(api, id); err != nil {
+		return err
+	}
+
+	if d.Get("reserve").(bool) {
+		return setIpAddressOwner(api, id, 0)
+	}
+
+	return nil
+}
+
+func resourceIpAddressImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		api := m.(*Config).getClient()
+
+		ip, err := findIpAddressByAddr(api, d.Id())
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(ip.Id, 10))
+	}
+
+	if err := resourceIpAddressRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid IP address id: %v", err)
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("IP address is not assigned to any VPS")
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
@@ -0,0 +1,298 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceIpAddressReadSetsAssignment(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v7.0/host_ip_addresses" {
+			assertQueryValue(t, r, "host_ip_address[ip_address]", "9")
+			assertQueryValue(t, r, "host_ip_address[addr]", "198.51.100.10")
+			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{
+				{Id: 42, Addr: "198.51.100.10", ReverseRecordValue: "mail.example.com."},
+			})
+			return
+		} else if r.URL.Path != "/v7.0/ip_addresses/9" {
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+			Id:     9,
+			Addr:   "198.51.100.10",
+			Prefix: 32,
+			Network: &client.ActionNetworkShowOutput{
+				Address:   "198.51.100.0",
+				Prefix:    24,
+				IpVersion: 4,
+				Role:      "public_access",
+			},
+			NetworkInterface: &client.ActionNetworkInterfaceShowOutput{
+				Name: "venet0",
+				Vps:  &client.ActionVpsShowOutput{Id: 123},
+			},
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceIpAddress().Schema, "9", nil, nil)
+
+	if err := resourceIpAddressRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertResourceValue(t, d, "vps", 123)
+	assertResourceValue(t, d, "address", "198.51.100.10")
+	assertResourceValue(t, d, "version", 4)
+	assertResourceValue(t, d, "role", "public_access")
+	assertResourceValue(t, d, "network", "198.51.100.0/24")
+	assertResourceValue(t, d, "network_interface", "venet0")
+	assertResourceValue(t, d, "reverse_record", "mail.example.com.")
+}
+
+func TestResourceIpAddressReadRemovesUnassignedAddress(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+			Id:   9,
+			Addr: "198.51.100.10",
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceIpAddress().Schema, "9", nil, nil)
+
+	if err := resourceIpAddressRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
+
+func TestResourceIpAddressUpdateMovesAddress(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/network_interfaces":
+			assertQueryValue(t, r, "network_interface[vps]", "456")
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 77, Name: "venet0"},
+			})
+		case "/v7.0/ip_addresses/9/free":
+			calls = append(calls, "free")
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressFreeOutput{})
+		case "/v7.0/ip_addresses/9/assign_with_host_address":
+			if body := readRequestBody(t, r); !strings.Contains(body, `"network_interface":77`) {
+				t.Errorf("assign body = %s", body)
+			}
+			calls = append(calls, "assign")
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressAssignWithHostAddressOutput{})
+		case "/v7.0/host_ip_addresses":
+			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{})
+		case "/v7.0/ip_addresses/9":
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+				Id:   9,
+				Addr: "198.51.100.10",
+				NetworkInterface: &client.ActionNetworkInterfaceShowOutput{
+					Name: "venet0",
+					Vps:  &client.ActionVpsShowOutput{Id: 456},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceIpAddress().Schema,
+		"9",
+		map[string]string{
+			"vps":     "123",
+			"address": "198.51.100.10",
+		},
+		map[string]*terraform.ResourceAttrDiff{
+			"vps": {
+				Old: "123",
+				New: "456",
+			},
+		},
+	)
+
+	if err := resourceIpAddressUpdate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "free,assign" {
+		t.Fatalf("calls = %v, want [free assign]", calls)
+	}
+	assertResourceValue(t, d, "vps", 456)
+}
+
+func TestResourceIpAddressUpdateAssignsUnassignedAddress(t *testing.T) {
+	var calls []string
+	var shown int
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/network_interfaces":
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 77, Name: "venet0"},
+			})
+		case "/v7.0/ip_addresses/9/free":
+			t.Errorf("unassigned address must not be freed")
+			writeAPIError(t, w, "IP address is not assigned")
+		case "/v7.0/ip_addresses/9/assign_with_host_address":
+			calls = append(calls, "assign")
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressAssignWithHostAddressOutput{})
+		case "/v7.0/host_ip_addresses":
+			writeAPIResponse(t, w, "host_ip_addresses", []*client.ActionHostIpAddressIndexOutput{})
+		case "/v7.0/ip_addresses/9":
+			shown++
+
+			ip := &client.ActionIpAddressShowOutput{Id: 9, Addr: "198.51.100.10"}
+
+			// The original VPS was destroyed, the address is assigned only
+			// after the update
+			if shown > 1 {
+				ip.NetworkInterface = &client.ActionNetworkInterfaceShowOutput{
+					Name: "venet0",
+					Vps:  &client.ActionVpsShowOutput{Id: 456},
+				}
+			}
+
+			writeAPIResponse(t, w, "ip_address", ip)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceIpAddress().Schema,
+		"9",
+		map[string]string{
+			"vps":     "123",
+			"address": "198.51.100.10",
+		},
+		map[string]*terraform.ResourceAttrDiff{
+			"vps": {
+				Old: "123",
+				New: "456",
+			},
+		},
+	)
+
+	if err := resourceIpAddressUpdate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "assign" {
+		t.Fatalf("calls = %v, want [assign]", calls)
+	}
+	assertResourceValue(t, d, "vps", 456)
+}
+
+func TestResourceIpAddressCreateReleasesReservationOnFailure(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:   123,
+				Node: testNode("node-a", "prg"),
+			})
+		case r.URL.Path == "/v7.0/ip_addresses":
+			writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+				{Id: 9, Addr: "198.51.100.10"},
+			})
+		case r.URL.Path == "/v7.0/network_interfaces":
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 77, Name: "venet0"},
+			})
+		case r.URL.Path == "/v7.0/users/current":
+			writeAPIResponse(t, w, "user", &client.ActionUserCurrentOutput{Id: 5})
+		case r.URL.Path == "/v7.0/ip_addresses/9" && r.Method == http.MethodPut:
+			body := readRequestBody(t, r)
+			if strings.Contains(body, `"user":5`) {
+				calls = append(calls, "reserve")
+			} else if strings.Contains(body, `"user":null`) {
+				calls = append(calls, "release")
+			} else {
+				t.Errorf("update body = %s", body)
+			}
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressUpdateOutput{})
+		case r.URL.Path == "/v7.0/ip_addresses/9/assign_with_host_address":
+			calls = append(calls, "assign")
+			writeAPIError(t, w, "address is in use")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceIpAddress().Schema, map[string]interface{}{
+		"vps":     123,
+		"address": "198.51.100.10",
+		"reserve": true,
+	})
+
+	if err := resourceIpAddressCreate(d, cfg); err == nil {
+		t.Fatal("resourceIpAddressCreate() error = nil, want error")
+	}
+
+	if strings.Join(calls, ",") != "reserve,assign,release" {
+		t.Fatalf("calls = %v, want [reserve assign release]", calls)
+	}
+}
+
+func TestResourceIpAddressDeleteReleasesReservation(t *testing.T) {
+	var calls []string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v7.0/ip_addresses/9/free":
+			calls = append(calls, "free")
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressFreeOutput{})
+		case r.URL.Path == "/v7.0/ip_addresses/9" && r.Method == http.MethodPut:
+			if body := readRequestBody(t, r); !strings.Contains(body, `"user":null`) {
+				t.Errorf("update body = %s", body)
+			}
+			calls = append(calls, "disown")
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressUpdateOutput{})
+		case r.URL.Path == "/v7.0/ip_addresses/9":
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+				Id:               9,
+				Addr:             "198.51.100.10",
+				NetworkInterface: &client.ActionNetworkInterfaceShowOutput{Name: "venet0"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceIpAddress().Schema,
+		"9",
+		map[string]string{
+			"reserve": "true",
+		},
+		nil,
+	)
+
+	if err := resourceIpAddressDelete(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "free,disown" {
+		t.Fatalf("calls = %v, want [free disown]", calls)
+	}
+}
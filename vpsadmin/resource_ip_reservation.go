@@ -0,0 +1,170 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+)
+
+func resourceIpReservation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceIpReservationCreate,
+		Read:   resourceIpReservationRead,
+		Delete: resourceIpReservationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceIpReservationImport,
+		},
+
+		Description: `
+Reserves an IP address for the current user independently of any VPS.
+Reserved addresses survive destruction of VPS they are assigned to and can be
+assigned again using *vpsadmin_ip_address*. A free address is picked by
+*version*, *role* and *location*, or a specific *address* can be reserved.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"address": &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "Specific address to reserve, an unassigned address without owner is picked if not set",
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentIpAddress,
+				ExactlyOneOf:     []string{"address", "location"},
+			},
+			"location": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Location label to pick the address from",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"address", "location"},
+			},
+			"version": &schema.Schema{
+				Type:          schema.TypeInt,
+				Description:   "IP version of the picked address, 4 or 6",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IntInSlice([]int{4, 6}),
+				ConflictsWith: []string{"address"},
+			},
+			"role": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Role of the picked address, public_access or private_access",
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.StringInSlice([]string{"public_access", "private_access"}, false),
+				ConflictsWith: []string{"address"},
+			},
+			"prefix": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Prefix of the routed address",
+				Computed:    true,
+			},
+			"network": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Network the address belongs to, in CIDR notation",
+				Computed:    true,
+			},
+			"vps": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "ID of the VPS the address is assigned to, 0 if unassigned",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceIpReservationCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	ip, err := pickIpAddress(api, d, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := reserveIpAddress(api, ip.Id); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(ip.Id, 10))
+
+	return resourceIpReservationRead(d, m)
+}
+
+func resourceIpReservationRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid IP address id: %v", err)
+	}
+
+	ip, err := ipAddressShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if ip.User == nil {
+		log.Printf("[INFO] IP address %s is no longer reserved, removing from state", ip.Addr)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("address", ip.Addr)
+	d.Set("prefix", ip.Prefix)
+	d.Set("vps", 0)
+
+	if ip.NetworkInterface != nil && ip.NetworkInterface.Vps != nil {
+		d.Set("vps", ip.NetworkInterface.Vps.Id)
+	}
+
+	if ip.Network != nil {
+		d.Set("version", ip.Network.IpVersion)
+		d.Set("role", ip.Network.Role)
+		d.Set("network", fmt.Sprintf("%s/%d", ip.Network.Address, ip.Network.Prefix))
+	}
+
+	return nil
+}
+
+func resourceIpReservationDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid IP address id: %v", err)
+	}
+
+	return setIpAddressOwner(api, id, 0)
+}
+
+func resourceIpReservationImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		api := m.(*Config).getClient()
+
+		ip, err := findIpAddressByAddr(api, d.Id())
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(ip.Id, 10))
+	}
+
+	if err := resourceIpReservationRead(d, m); err != nil {
+		return nil, fmt.Errorf("invalid IP address id: %v", err)
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("IP address is not reserved")
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
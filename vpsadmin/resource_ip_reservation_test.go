@@ -0,0 +1,92 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceIpReservationCreateReservesUnownedAddress(t *testing.T) {
+	var ownerBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/locations":
+			writeAPIResponse(t, w, "locations", []*client.ActionLocationListOutput{
+				{Id: 3, Label: "prg"},
+			})
+		case "/v7.0/ip_addresses":
+			assertQueryValue(t, r, "ip_address[location]", "3")
+			assertQueryValue(t, r, "ip_address[version]", "4")
+			assertQueryValue(t, r, "ip_address[assigned_to_interface]", "0")
+			if _, ok := r.URL.Query()["ip_address[user]"]; !ok {
+				t.Errorf("free addresses are not filtered by missing owner")
+			}
+			writeAPIResponse(t, w, "ip_addresses", []*client.ActionIpAddressIndexOutput{
+				{Id: 8, Addr: "198.51.100.9", User: &client.ActionUserShowOutput{Id: 5}},
+				{Id: 9, Addr: "198.51.100.10"},
+			})
+		case "/v7.0/users/current":
+			writeAPIResponse(t, w, "user", &client.ActionUserCurrentOutput{Id: 7})
+		case "/v7.0/ip_addresses/9":
+			if r.Method == http.MethodPut {
+				ownerBody = readRequestBody(t, r)
+				writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressUpdateOutput{})
+				return
+			}
+
+			writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressShowOutput{
+				Id:   9,
+				Addr: "198.51.100.10",
+				User: &client.ActionUserShowOutput{Id: 7},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceIpReservation().Schema, map[string]interface{}{
+		"location": "prg",
+	})
+
+	if err := resourceIpReservationCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(ownerBody, `"user":7`) {
+		t.Fatalf("update body = %s", ownerBody)
+	}
+	if d.Id() != "9" {
+		t.Fatalf("id = %q, want 9", d.Id())
+	}
+	assertResourceValue(t, d, "address", "198.51.100.10")
+	assertResourceValue(t, d, "vps", 0)
+}
+
+func TestResourceIpReservationDeleteReleasesOwnership(t *testing.T) {
+	var ownerBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/ip_addresses/9" || r.Method != http.MethodPut {
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		ownerBody = readRequestBody(t, r)
+		writeAPIResponse(t, w, "ip_address", &client.ActionIpAddressUpdateOutput{})
+	})
+
+	d := newResourceDataWithDiff(t, resourceIpReservation().Schema, "9", nil, nil)
+
+	if err := resourceIpReservationDelete(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(ownerBody, `"user":null`) {
+		t.Fatalf("update body = %s", ownerBody)
+	}
+}
@@ -1,10 +1,12 @@
 package vpsadmin
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"log"
 	"strconv"
+	"strings"
 )
 
 func resourceMount() *schema.Resource {
@@ -16,8 +18,12 @@ func resourceMount() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceMountImport,
 		},
+		CustomizeDiff: resourceMountCustomizeDiff,
 
-		Description: "Mount VPS subdatasets into VPS.",
+		Description: `
+Mount VPS subdatasets into VPS. Snapshots of datasets, including snapshots
+kept only in backups, can be mounted read-only for file-level restore.
+`,
 
 		Schema: map[string]*schema.Schema{
 			"vps": &schema.Schema{
@@ -27,10 +33,19 @@ func resourceMount() *schema.Resource {
 				ForceNew:    true,
 			},
 			"dataset": &schema.Schema{
-				Type:        schema.TypeInt,
-				Description: "ID of the mounted dataset",
-				Required:    true,
-				ForceNew:    true,
+				Type:         schema.TypeInt,
+				Description:  "ID of the mounted dataset",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "snapshot"},
+			},
+			"snapshot": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "ID of the mounted snapshot, e.g. from vpsadmin_dataset_snapshots, snapshots are always mounted read-only",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"dataset", "snapshot"},
 			},
 			"mountpoint": &schema.Schema{
 				Type:        schema.TypeString,
@@ -50,6 +65,9 @@ func resourceMount() *schema.Resource {
 				Default:     "rw",
 				Optional:    true,
 				ForceNew:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Get("snapshot").(int) != 0
+				},
 			},
 			"on_start_fail": &schema.Schema{
 				Type:        schema.TypeString,
@@ -61,6 +79,26 @@ func resourceMount() *schema.Resource {
 	}
 }
 
+func resourceMountCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	mode := config.GetAttr("mode")
+	snapshot := config.GetAttr("snapshot")
+
+	if snapshot.IsNull() || mode.IsNull() || !mode.IsKnown() {
+		return nil
+	}
+
+	if mode.AsString() != "ro" {
+		return fmt.Errorf("Snapshots can be mounted only in read-only mode, set mode to ro")
+	}
+
+	return nil
+}
+
 func resourceMountCreate(d *schema.ResourceData, m interface{}) error {
 	api := m.(*Config).getClient()
 
@@ -68,10 +106,17 @@ func resourceMountCreate(d *schema.ResourceData, m interface{}) error {
 	create.SetPathParamInt("vps_id", int64(d.Get("vps").(int)))
 
 	input := create.NewInput()
-	input.SetDataset(int64(d.Get("dataset").(int)))
+
+	if v, ok := d.GetOk("snapshot"); ok {
+		input.SetSnapshot(int64(v.(int)))
+		input.SetMode("ro")
+	} else {
+		input.SetDataset(int64(d.Get("dataset").(int)))
+		input.SetMode(d.Get("mode").(string))
+	}
+
 	input.SetMountpoint(d.Get("mountpoint").(string))
 	input.SetEnabled(d.Get("enable").(bool))
-	input.SetMode(d.Get("mode").(string))
 	input.SetOnStartFail(d.Get("on_start_fail").(string))
 
 	resp, err := create.Call()
@@ -105,6 +150,13 @@ func resourceMountRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.Set("dataset", mount.Dataset.Id)
+
+	if mount.Snapshot != nil {
+		d.Set("snapshot", mount.Snapshot.Id)
+	} else {
+		d.Set("snapshot", nil)
+	}
+
 	d.Set("mountpoint", mount.Mountpoint)
 	d.Set("enable", mount.Enabled)
 	d.Set("mode", mount.Mode)
@@ -184,17 +236,32 @@ func resourceMountDelete(d *schema.ResourceData, m interface{}) error {
 func resourceMountImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	api := m.(*Config).getClient()
 
-	id, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return nil, fmt.Errorf("Invalid mount id: %v", err)
-	}
+	if strings.Contains(d.Id(), "/") {
+		vpsId, mountpoint, err := parseMountImportId(d.Id())
+		if err != nil {
+			return nil, err
+		}
 
-	mount, err := mountFindById(api, id)
-	if err != nil {
-		return nil, err
-	}
+		mount, err := mountFindByMountpoint(api, vpsId, mountpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(mount.Id, 10))
+		d.Set("vps", vpsId)
+	} else {
+		id, err := strconv.Atoi(d.Id())
+		if err != nil {
+			return nil, fmt.Errorf("Invalid mount id: %v", err)
+		}
 
-	d.Set("vps", mount.Vps.Id)
+		mount, err := mountFindById(api, id)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Set("vps", mount.Vps.Id)
+	}
 
 	if err := resourceMountRead(d, m); err != nil {
 		return nil, fmt.Errorf("invalid mount id: %v", err)
@@ -0,0 +1,182 @@
+package vpsadmin
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"strconv"
+)
+
+func resourceNetworkInterface() *schema.Resource {
+	s := networkInterfaceSchema(false)
+	s["vps"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Description: "ID of the VPS the interface belongs to",
+		Required:    true,
+		ForceNew:    true,
+	}
+	s["interface_id"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Description: "ID of the interface to manage, required if the VPS has more than one interface",
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceNetworkInterfaceCreate,
+		Read:   resourceNetworkInterfaceRead,
+		Update: resourceNetworkInterfaceUpdate,
+		Delete: resourceNetworkInterfaceDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetworkInterfaceImport,
+		},
+
+		Description: `
+Manages the network interface of a VPS. The interface is created together with
+the VPS, this resource only configures its name and traffic shaping limits.
+Destroying the resource leaves the interface as it is.
+`,
+
+		Schema: s,
+	}
+}
+
+func resourceNetworkInterfaceCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	vps, err := vpsShow(api, d.Get("vps").(int))
+	if err != nil {
+		return err
+	}
+
+	netif, err := getVpsNetworkInterface(
+		api,
+		vps.Id,
+		int64(d.Get("interface_id").(int)),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(netif.Id, 10))
+
+	if err := updateNetworkInterface(api, netif.Id, d, true); err != nil {
+		return err
+	}
+
+	return resourceNetworkInterfaceRead(d, m)
+}
+
+func resourceNetworkInterfaceRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid network interface id: %v", err)
+	}
+
+	netif, err := networkInterfaceShow(api, id)
+	if err != nil {
+		return err
+	}
+
+	if netif.Vps != nil {
+		d.Set("vps", netif.Vps.Id)
+	}
+
+	setNetworkInterfaceAttributes(d, netif)
+
+	return nil
+}
+
+func resourceNetworkInterfaceUpdate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid network interface id: %v", err)
+	}
+
+	if err := updateNetworkInterface(api, id, d, false); err != nil {
+		return err
+	}
+
+	return resourceNetworkInterfaceRead(d, m)
+}
+
+func resourceNetworkInterfaceDelete(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[INFO] Network interface %s is removed from state, it is deleted with its VPS", d.Id())
+	return nil
+}
+
+func resourceNetworkInterfaceImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	err := resourceNetworkInterfaceRead(d, m)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid network interface id: %v", err)
+	}
+
+	results := make([]*schema.ResourceData, 1)
+	results[0] = d
+
+	return results, nil
+}
+
+// updateNetworkInterface sends configured attributes to vpsAdmin, on create
+// all set attributes are sent, including explicit 0 for unlimited throughput,
+// otherwise only the changed ones
+func updateNetworkInterface(api *client.Client, id int64, d *schema.ResourceData, create bool) error {
+	update := api.NetworkInterface.Update.Prepare()
+	update.SetPathParamInt("network_interface_id", id)
+
+	input := update.NewInput()
+
+	if create {
+		if v, ok := d.GetOk("name"); ok {
+			input.SetName(v.(string))
+		}
+
+		if v, ok := d.GetOkExists("max_tx"); ok {
+			input.SetMaxTx(int64(v.(int)))
+		}
+
+		if v, ok := d.GetOkExists("max_rx"); ok {
+			input.SetMaxRx(int64(v.(int)))
+		}
+	} else {
+		if d.HasChange("name") {
+			input.SetName(d.Get("name").(string))
+		}
+
+		if d.HasChange("max_tx") {
+			input.SetMaxTx(int64(d.Get("max_tx").(int)))
+		}
+
+		if d.HasChange("max_rx") {
+			input.SetMaxRx(int64(d.Get("max_rx").(int)))
+		}
+	}
+
+	if !input.AnySelected() {
+		return nil
+	}
+
+	log.Printf("[INFO] Updating network interface %d: %+v", id, input)
+
+	resp, err := update.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Network interface update failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Network interface update failed: %v", err)
+	}
+
+	return nil
+}
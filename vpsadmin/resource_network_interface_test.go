@@ -0,0 +1,161 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceNetworkInterfaceCreateConfiguresInterface(t *testing.T) {
+	var updateBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:   123,
+				Node: testNode("node-a", "prg"),
+			})
+		case "/v7.0/network_interfaces":
+			assertQueryValue(t, r, "network_interface[vps]", "123")
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 55, Name: "venet0"},
+			})
+		case "/v7.0/network_interfaces/55":
+			if r.Method == http.MethodPut {
+				updateBody = readRequestBody(t, r)
+				writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceUpdateOutput{})
+				return
+			}
+
+			writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceShowOutput{
+				Id:    55,
+				Vps:   &client.ActionVpsShowOutput{Id: 123},
+				Name:  "eth0",
+				MaxTx: 1000,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetworkInterface().Schema, map[string]interface{}{
+		"vps":    123,
+		"name":   "eth0",
+		"max_tx": 1000,
+	})
+
+	if err := resourceNetworkInterfaceCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(updateBody, `"name":"eth0"`) ||
+		!strings.Contains(updateBody, `"max_tx":1000`) ||
+		strings.Contains(updateBody, `"max_rx"`) {
+		t.Fatalf("update body = %s", updateBody)
+	}
+	if d.Id() != "55" {
+		t.Fatalf("id = %q, want 55", d.Id())
+	}
+	assertResourceValue(t, d, "name", "eth0")
+	assertResourceValue(t, d, "max_tx", 1000)
+}
+
+func TestResourceNetworkInterfaceCreateSendsUnlimitedThroughput(t *testing.T) {
+	var updateBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v7.0/vpses/123":
+			writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+				Id:   123,
+				Node: testNode("node-a", "prg"),
+			})
+		case "/v7.0/network_interfaces":
+			writeAPIResponse(t, w, "network_interfaces", []*client.ActionNetworkInterfaceIndexOutput{
+				{Id: 55, Name: "venet0"},
+			})
+		case "/v7.0/network_interfaces/55":
+			if r.Method == http.MethodPut {
+				updateBody = readRequestBody(t, r)
+				writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceUpdateOutput{})
+				return
+			}
+
+			writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceShowOutput{
+				Id:   55,
+				Vps:  &client.ActionVpsShowOutput{Id: 123},
+				Name: "venet0",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetworkInterface().Schema, map[string]interface{}{
+		"vps":    123,
+		"max_tx": 0,
+		"max_rx": 0,
+	})
+
+	if err := resourceNetworkInterfaceCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(updateBody, `"max_tx":0`) || !strings.Contains(updateBody, `"max_rx":0`) {
+		t.Fatalf("update body = %s, want unlimited max_tx and max_rx", updateBody)
+	}
+}
+
+func TestResourceNetworkInterfaceUpdateSendsOnlyChanges(t *testing.T) {
+	var updateBody string
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/network_interfaces/55" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Method == http.MethodPut {
+			updateBody = readRequestBody(t, r)
+			writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceUpdateOutput{})
+			return
+		}
+
+		writeAPIResponse(t, w, "network_interface", &client.ActionNetworkInterfaceShowOutput{
+			Id:   55,
+			Name: "eth0",
+		})
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceNetworkInterface().Schema,
+		"55",
+		map[string]string{
+			"name":   "eth0",
+			"max_tx": "1000",
+			"max_rx": "2000",
+		},
+		map[string]*terraform.ResourceAttrDiff{
+			"max_tx": {
+				Old: "1000",
+				New: "0",
+			},
+		},
+	)
+
+	if err := resourceNetworkInterfaceUpdate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(updateBody, `"max_tx":0`) ||
+		strings.Contains(updateBody, `"name"`) ||
+		strings.Contains(updateBody, `"max_rx"`) {
+		t.Fatalf("update body = %s", updateBody)
+	}
+}
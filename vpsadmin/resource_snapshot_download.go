@@ -0,0 +1,253 @@
+package vpsadmin
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
+	"strconv"
+	"time"
+)
+
+// snapshotDownloadPollInterval is the time between checks whether a download
+// is ready
+var snapshotDownloadPollInterval = 10 * time.Second
+
+func resourceSnapshotDownload() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSnapshotDownloadCreate,
+		Read:   resourceSnapshotDownloadRead,
+		Delete: resourceSnapshotDownloadDelete,
+
+		CustomizeDiff: resourceSnapshotDownloadCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Description: `
+Requests a downloadable archive of a snapshot and waits until it is ready.
+Downloads expire after a while, an expired download is requested again on the
+next apply. The download is deleted when the resource is destroyed.
+
+Creation waits until the download is ready, large snapshots may need a longer
+*create* timeout.
+`,
+
+		Schema: map[string]*schema.Schema{
+			"snapshot": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Snapshot ID, e.g. from vpsadmin_dataset_snapshot",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"from_snapshot": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Snapshot ID the incremental stream starts from, required by format incremental_stream",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"format": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "archive for tar.gz, stream for a full ZFS stream or incremental_stream for a ZFS stream from from_snapshot",
+				Optional:     true,
+				Default:      "archive",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"archive", "stream", "incremental_stream"}, false),
+			},
+			"send_mail": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Send an e-mail when the download is ready",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"file_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "File name",
+				Computed:    true,
+			},
+			"url": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Download URL",
+				Computed:    true,
+			},
+			"size": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "File size, in MiB",
+				Computed:    true,
+			},
+			"sha256sum": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "SHA256 checksum of the file",
+				Computed:    true,
+			},
+			"expiration_date": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Date when the download is deleted",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceSnapshotDownloadCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	_, fromSnapshot := d.GetOk("from_snapshot")
+	incremental := d.Get("format").(string) == "incremental_stream"
+
+	if incremental && !fromSnapshot {
+		return fmt.Errorf("from_snapshot is required by format incremental_stream")
+	} else if !incremental && fromSnapshot {
+		return fmt.Errorf("from_snapshot can be used only with format incremental_stream")
+	}
+
+	return nil
+}
+
+func resourceSnapshotDownloadCreate(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	create := api.SnapshotDownload.Create.Prepare()
+
+	input := create.NewInput()
+	input.SetSnapshot(int64(d.Get("snapshot").(int)))
+	input.SetFormat(d.Get("format").(string))
+	input.SetSendMail(d.Get("send_mail").(bool))
+
+	if v, ok := d.GetOk("from_snapshot"); ok {
+		input.SetFromSnapshot(int64(v.(int)))
+	}
+
+	log.Printf("[INFO] Requesting download of snapshot %d", d.Get("snapshot").(int))
+
+	resp, err := create.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Snapshot download creation failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Snapshot download creation failed: %v", err)
+	}
+
+	d.SetId(strconv.FormatInt(resp.Output.Id, 10))
+
+	if err := waitForSnapshotDownload(api, resp.Output.Id, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	return resourceSnapshotDownloadRead(d, m)
+}
+
+func resourceSnapshotDownloadRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot download id: %v", err)
+	}
+
+	dl, err := findSnapshotDownload(api, id)
+	if err != nil {
+		return err
+	}
+
+	if dl == nil {
+		log.Printf("[INFO] Snapshot download %d not found, removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	if dl.Snapshot != nil {
+		d.Set("snapshot", dl.Snapshot.Id)
+	}
+
+	if dl.FromSnapshot != nil {
+		d.Set("from_snapshot", dl.FromSnapshot.Id)
+	}
+
+	d.Set("format", dl.Format)
+	d.Set("file_name", dl.FileName)
+	d.Set("url", dl.Url)
+	d.Set("size", dl.Size)
+	d.Set("sha256sum", dl.Sha256sum)
+	d.Set("expiration_date", dl.ExpirationDate)
+
+	return nil
+}
+
+func resourceSnapshotDownloadDelete(d *schema.ResourceData, m interface{}) error {
+	api := m.(*Config).getClient()
+
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot download id: %v", err)
+	}
+
+	log.Printf("[INFO] Deleting snapshot download: %s", d.Id())
+
+	del := api.SnapshotDownload.Delete.Prepare()
+	del.SetPathParamInt("snapshot_download_id", id)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("Snapshot download deletion failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("Snapshot download deletion failed: %v", err)
+	}
+
+	return nil
+}
+
+// findSnapshotDownload returns nil if the download does not exist, e.g. when
+// it has expired
+func findSnapshotDownload(api *client.Client, id int64) (*client.ActionSnapshotDownloadShowOutput, error) {
+	show := api.SnapshotDownload.Show.Prepare()
+	show.SetPathParamInt("snapshot_download_id", id)
+	show.SetMetaInput(&client.ActionSnapshotDownloadShowMetaGlobalInput{
+		Includes: "snapshot,from_snapshot",
+	})
+	show.MetaInput.SelectParameters("Includes")
+
+	resp, err := show.Call()
+
+	if err != nil {
+		return nil, err
+	} else if !resp.Status {
+		log.Printf("[DEBUG] Snapshot download %d not found: %s", id, resp.Message)
+		return nil, nil
+	}
+
+	return resp.Output, nil
+}
+
+func waitForSnapshotDownload(api *client.Client, id int64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		dl, err := findSnapshotDownload(api, id)
+		if err != nil {
+			return err
+		} else if dl == nil {
+			return fmt.Errorf("Snapshot download %d disappeared", id)
+		} else if dl.Ready {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Snapshot download %d did not become ready in %s", id, timeout)
+		}
+
+		log.Printf("[INFO] Waiting for snapshot download %d to become ready", id)
+		time.Sleep(snapshotDownloadPollInterval)
+	}
+}
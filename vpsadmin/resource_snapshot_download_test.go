@@ -0,0 +1,99 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceSnapshotDownloadCreateWaitsUntilReady(t *testing.T) {
+	interval := snapshotDownloadPollInterval
+	snapshotDownloadPollInterval = 0
+	defer func() { snapshotDownloadPollInterval = interval }()
+
+	var createBody string
+	polls := 0
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v7.0/snapshot_downloads":
+			createBody = readRequestBody(t, r)
+			writeAPIResponse(t, w, "snapshot_download", &client.ActionSnapshotDownloadCreateOutput{Id: 3})
+		case r.Method == http.MethodGet && r.URL.Path == "/v7.0/snapshot_downloads/3":
+			polls++
+			writeAPIResponse(t, w, "snapshot_download", &client.ActionSnapshotDownloadShowOutput{
+				Id:           3,
+				Snapshot:     &client.ActionDatasetSnapshotShowOutput{Id: 5},
+				FromSnapshot: &client.ActionDatasetSnapshotShowOutput{Id: 4},
+				Format:       "incremental_stream",
+				FileName:     "vps123-5.inc.dat.gz",
+				Url:          "https://download.example.com/vps123-5.inc.dat.gz",
+				Size:         512,
+				Sha256sum:    "abc123",
+				Ready:        polls > 1,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceSnapshotDownload().Schema, map[string]interface{}{
+		"snapshot":      5,
+		"from_snapshot": 4,
+		"format":        "incremental_stream",
+	})
+
+	if err := resourceSnapshotDownloadCreate(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"snapshot":5`, `"from_snapshot":4`, `"format":"incremental_stream"`} {
+		if !strings.Contains(createBody, want) {
+			t.Fatalf("create body = %s, want %s", createBody, want)
+		}
+	}
+	if polls < 2 {
+		t.Fatalf("polls = %d, want at least 2", polls)
+	}
+	assertResourceValue(t, d, "url", "https://download.example.com/vps123-5.inc.dat.gz")
+	assertResourceValue(t, d, "size", 512)
+	assertResourceValue(t, d, "sha256sum", "abc123")
+}
+
+func TestWaitForSnapshotDownloadTimesOut(t *testing.T) {
+	interval := snapshotDownloadPollInterval
+	snapshotDownloadPollInterval = 0
+	defer func() { snapshotDownloadPollInterval = interval }()
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResponse(t, w, "snapshot_download", &client.ActionSnapshotDownloadShowOutput{Id: 3})
+	})
+
+	err := waitForSnapshotDownload(cfg.getClient(), 3, 0)
+	if err == nil || !strings.Contains(err.Error(), "did not become ready") {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+}
+
+func TestResourceSnapshotDownloadReadRemovesExpiredDownload(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/snapshot_downloads/3" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		writeAPIError(t, w, "object not found")
+	})
+
+	d := newResourceDataWithDiff(t, resourceSnapshotDownload().Schema, "3", nil, nil)
+
+	if err := resourceSnapshotDownloadRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
@@ -201,6 +201,22 @@ func resourceSshKeyDelete(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceSshKeyImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if label, ok := strings.CutPrefix(d.Id(), "label:"); ok {
+		api := m.(*Config).getClient()
+
+		user, err := getCurrentUser(api)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := getPublicKeyByLabel(api, user.Id, label)
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(strconv.FormatInt(key.Id, 10))
+	}
+
 	err := resourceSshKeyRead(d, m)
 
 	if err != nil {
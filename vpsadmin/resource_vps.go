@@ -8,6 +8,7 @@ import (
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
 	"log"
 	"strconv"
+	"strings"
 )
 
 func resourceVps() *schema.Resource {
@@ -27,11 +28,18 @@ func resourceVps() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceVpsImport,
 		},
+		CustomizeDiff: resourceVpsCustomizeDiff,
 
 		Description: `
 Represents a virtual server instance. To create a VPS, you need to have
 a sufficient amount of resources assigned to your account in vpsAdmin. Contact
 support in case you need more.
+
+vpsAdmin configures only its own DNS resolvers, see *dns_resolver*, it cannot
+push custom nameservers to the VPS. To use other nameservers, set
+*manage_dns_resolver* to false and deploy /etc/resolv.conf yourself, e.g.
+rendered by ` + "`templatefile(\"resolv.conf.tftpl\", { nameservers = [...] })`" + `
+and uploaded by a file provisioner.
 `,
 
 		Schema: map[string]*schema.Schema{
@@ -82,7 +90,7 @@ support in case you need more.
 			},
 			"dns_resolver": &schema.Schema{
 				Type:          schema.TypeString,
-				Description:   "DNS resolver used by the VPS if managed by vpsAdmin",
+				Description:   "DNS resolver used by the VPS if managed by vpsAdmin, see data source vpsadmin_dns_resolvers",
 				Computed:      true,
 				Optional:      true,
 				ConflictsWith: []string{"manage_dns_resolver"},
@@ -130,35 +138,27 @@ support in case you need more.
 				Description: "Primary public IPv6 address",
 				Computed:    true,
 			},
+			"ipv4_addresses":         ipAddressListSchema("All public IPv4 addresses"),
+			"private_ipv4_addresses": ipAddressListSchema("All private IPv4 addresses"),
+			"ipv6_addresses":         ipAddressListSchema("All public IPv6 addresses"),
+			"network_config":         networkConfigSchema(),
 			"public_ipv4_count": &schema.Schema{
 				Type:        schema.TypeInt,
-				Description: "Number of public IPv4 addresses to add when the VPS is created",
-				Default:     1,
+				Description: "Number of public IPv4 addresses assigned to the VPS, defaults to 1 on create. When set, extra addresses are freed, most recently added first, or missing ones added.",
 				Optional:    true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					// This field is used only when the VPS is being created
-					return d.Id() != ""
-				},
+				Computed:    true,
 			},
 			"private_ipv4_count": &schema.Schema{
 				Type:        schema.TypeInt,
-				Description: "Number of private IPv4 addresses to add when the VPS is created",
-				Default:     0,
+				Description: "Number of private IPv4 addresses assigned to the VPS, defaults to 0 on create. When set, extra addresses are freed, most recently added first, or missing ones added.",
 				Optional:    true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					// This field is used only when the VPS is being created
-					return d.Id() != ""
-				},
+				Computed:    true,
 			},
 			"public_ipv6_count": &schema.Schema{
 				Type:        schema.TypeInt,
-				Description: "Number of public IPv6 addresses to add when the VPS is created",
-				Default:     1,
+				Description: "Number of public IPv6 addresses assigned to the VPS, defaults to 1 on create. When set, extra addresses are freed, most recently added first, or missing ones added.",
 				Optional:    true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					// This field is used only when the VPS is being created
-					return d.Id() != ""
-				},
+				Computed:    true,
 			},
 			"ssh_keys": &schema.Schema{
 				Type:        schema.TypeSet,
@@ -205,10 +205,65 @@ support in case you need more.
 				Computed:    true,
 				Optional:    true,
 			},
+			"maintenance_window":  maintenanceWindowSchema(),
+			"deletion_protection": deletionProtectionSchema("VPS"),
+			"destroy_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "hard to delete the VPS immediately, soft to only mark it as deleted, so that it can be restored by vpsAdmin administrators until it expires",
+				Default:      "hard",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"hard", "soft"}, false),
+			},
+			"object_state": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Lifetime state of the VPS in vpsAdmin",
+				Computed:    true,
+			},
+			"expiration_date": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Date when the VPS leaves its current lifetime state",
+				Computed:    true,
+			},
+			"connection_address_preference": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Order in which address kinds are considered for provisioner connections, defaults to public_ipv4, public_ipv6, private_ipv4",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(defaultConnectionAddressPreference, false),
+				},
+			},
+			"connection_user": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "User name for provisioner connections",
+				Optional:    true,
+			},
+			"connection_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "SSH port for provisioner connections",
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
 		},
 	}
 }
 
+func resourceVpsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	label := d.Get("dns_resolver").(string)
+
+	if m == nil || label == "" || !d.Get("manage_dns_resolver").(bool) {
+		return nil
+	} else if !d.HasChanges("dns_resolver", "location") {
+		return nil
+	} else if !d.NewValueKnown("dns_resolver") || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	api := m.(*Config).getClient()
+
+	return validateDnsResolver(api, label, d.Get("location").(string))
+}
+
 func resourceVpsCreate(d *schema.ResourceData, m interface{}) error {
 	api := m.(*Config).getClient()
 
@@ -253,9 +308,9 @@ func resourceVpsCreate(d *schema.ResourceData, m interface{}) error {
 	input.SetMemory(int64(d.Get("memory").(int)))
 	input.SetSwap(int64(d.Get("swap").(int)))
 	input.SetDiskspace(int64(d.Get("diskspace").(int)))
-	input.SetIpv4(int64(d.Get("public_ipv4_count").(int)))
-	input.SetIpv4Private(int64(d.Get("private_ipv4_count").(int)))
-	input.SetIpv6(int64(d.Get("public_ipv6_count").(int)))
+	input.SetIpv4(int64(getVpsIpAddressCount(d, "public_ipv4_count", 1)))
+	input.SetIpv4Private(int64(getVpsIpAddressCount(d, "private_ipv4_count", 0)))
+	input.SetIpv6(int64(getVpsIpAddressCount(d, "public_ipv6_count", 1)))
 
 	if v, ok := d.GetOk("start_menu_timeout"); ok {
 		input.SetStartMenuTimeout(int64(v.(int)))
@@ -331,6 +386,13 @@ func resourceVpsCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("VPS feature set failed: %v", err)
 	}
 
+	// Maintenance windows
+	if v, ok := d.GetOk("maintenance_window"); ok {
+		if err := setVpsMaintenanceWindows(api, resp.Output.Id, v.(*schema.Set).List()); err != nil {
+			return err
+		}
+	}
+
 	// SSH keys
 	if keys, ok := d.GetOk("ssh_keys"); ok {
 		if err := deploySshKeys(api, resp.Output.Id, keys.(*schema.Set).List()); err != nil {
@@ -356,21 +418,53 @@ func resourceVpsRead(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
+	if isVpsDeleted(vps) {
+		log.Printf("[INFO] VPS %d is in state %s, removing from state", vps.Id, vps.ObjectState)
+		d.SetId("")
+		return nil
+	}
+
 	// Dataset cannot be prefetched, API limitation
 	ds, err := datasetShow(api, int(vps.Dataset.Id))
 	if err != nil {
 		return err
 	}
 
-	publicIpv4 := getPrimaryPublicHostIpv4Address(api, vps.Id)
-	privateIpv4 := getPrimaryPrivateHostIpv4Address(api, vps.Id)
-	publicIpv6 := getPrimaryPublicHostIpv6Address(api, vps.Id)
+	hostAddrs, err := getVpsHostIpAddresses(api, vps.Id)
+	if err != nil {
+		return err
+	}
+
+	netifs, err := vpsNetworkInterfaceList(api, vps.Id)
+	if err != nil {
+		return err
+	}
+
+	publicIpv4 := primaryHostIpAddress(hostAddrs.publicIpv4)
+	privateIpv4 := primaryHostIpAddress(hostAddrs.privateIpv4)
+	publicIpv6 := primaryHostIpAddress(hostAddrs.publicIpv6)
 
 	features, err := vpsFeatureList(api, id)
 	if err != nil {
 		return err
 	}
 
+	ipCounts := make(map[string]int)
+
+	for _, c := range vpsIpAddressCounts {
+		addrs, err := vpsIpAddressList(api, vps.Id, c.version, c.role)
+		if err != nil {
+			return err
+		}
+
+		ipCounts[c.attr] = len(addrs)
+	}
+
+	windows, err := vpsMaintenanceWindowList(api, vps.Id)
+	if err != nil {
+		return err
+	}
+
 	d.Set("location", vps.Node.Location.Label)
 	d.Set("node", vps.Node.DomainName)
 	d.Set("installed_os_template", vps.OsTemplate.Name)
@@ -392,13 +486,28 @@ func resourceVpsRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("public_ipv4_address", publicIpv4)
 	d.Set("private_ipv4_address", privateIpv4)
 	d.Set("public_ipv6_address", publicIpv6)
+	d.Set("ipv4_addresses", flattenHostIpAddresses(hostAddrs.publicIpv4))
+	d.Set("private_ipv4_addresses", flattenHostIpAddresses(hostAddrs.privateIpv4))
+	d.Set("ipv6_addresses", flattenHostIpAddresses(hostAddrs.publicIpv6))
+	d.Set("network_config", flattenNetworkConfig(netifs, hostAddrs.all()))
+
+	for attr, count := range ipCounts {
+		d.Set(attr, count)
+	}
+
+	preference := defaultConnectionAddressPreference
+
+	if v, ok := d.GetOk("connection_address_preference"); ok {
+		preference = make([]string, 0, len(v.([]interface{})))
 
-	if addr := getPrimaryConnectionAddress(publicIpv4, privateIpv4, publicIpv6); addr != "" {
+		for _, kind := range v.([]interface{}) {
+			preference = append(preference, kind.(string))
+		}
+	}
+
+	if addr := getPreferredConnectionAddress(preference, publicIpv4, privateIpv4, publicIpv6); addr != "" {
 		log.Printf("[INFO] Setting connection host to: '%s'", addr)
-		d.SetConnInfo(map[string]string{
-			"type": "ssh",
-			"host": addr,
-		})
+		d.SetConnInfo(vpsConnInfo(d, addr))
 	} else {
 		log.Printf("[INFO] No connection host found")
 	}
@@ -410,6 +519,14 @@ func resourceVpsRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.Set("start_menu_timeout", vps.StartMenuTimeout)
+	// Default windows are kept out of state unless they are configured
+	if d.Get("maintenance_window").(*schema.Set).Len() == 0 && isDefaultMaintenanceWindows(windows) {
+		d.Set("maintenance_window", nil)
+	} else {
+		d.Set("maintenance_window", flattenMaintenanceWindows(windows))
+	}
+	d.Set("object_state", vps.ObjectState)
+	d.Set("expiration_date", vps.ExpirationDate)
 
 	return nil
 }
@@ -516,6 +633,24 @@ func resourceVpsUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	if d.HasChanges("public_ipv4_count", "private_ipv4_count", "public_ipv6_count") {
+		vps, err := vpsShow(api, id)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range vpsIpAddressCounts {
+			if !d.HasChange(c.attr) {
+				continue
+			}
+
+			err := reconcileVpsIpAddressCount(api, vps, c.version, c.role, d.Get(c.attr).(int))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if hasAnyVpsFeatureChange(d) {
 		featureSet := api.Vps.Feature.UpdateAll.Prepare()
 		featureSet.SetPathParamInt("vps_id", int64(id))
@@ -549,6 +684,18 @@ func resourceVpsUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	if d.HasChange("maintenance_window") {
+		windows := d.Get("maintenance_window").(*schema.Set).List()
+
+		if len(windows) == 0 {
+			windows = defaultMaintenanceWindows()
+		}
+
+		if err := setVpsMaintenanceWindows(api, int64(id), windows); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("ssh_keys") {
 		if err := deploySshKeys(api, int64(id), d.Get("ssh_keys").(*schema.Set).List()); err != nil {
 			return err
@@ -569,34 +716,52 @@ func resourceVpsDelete(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("Invalid VPS id: %v", err)
 	}
 
-	log.Printf("[INFO] Deleting VPS: %s", d.Id())
+	if err := checkDeletionProtection(d, "VPS"); err != nil {
+		return err
+	}
+
+	return deleteVps(api, int64(id), d.Get("destroy_mode").(string) == "soft")
+}
+
+func resourceVpsImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	api := m.(*Config).getClient()
 
-	del := api.Vps.Delete.Prepare()
-	del.SetPathParamInt("vps_id", int64(id))
+	if hostname, ok := strings.CutPrefix(d.Id(), "hostname:"); ok {
+		vpsId, err := findVpsIdByHostname(api, hostname)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := del.Call()
+		d.SetId(strconv.FormatInt(vpsId, 10))
+	}
 
+	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return err
-	} else if !resp.Status {
-		return fmt.Errorf("VPS deletion failed: %s", resp.Message)
+		return nil, fmt.Errorf("invalid VPS id: %v", err)
 	}
 
-	if err := waitForOperation(resp); err != nil {
-		return fmt.Errorf("VPS deletion failed: %v", err)
+	vps, err := vpsShow(api, id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VPS id: %v", err)
 	}
 
-	return nil
-}
+	if isVpsDeleted(vps) {
+		return nil, fmt.Errorf(
+			"VPS %d is %s, have it restored in vpsAdmin before importing it",
+			vps.Id,
+			vps.ObjectState,
+		)
+	}
 
-func resourceVpsImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	err := resourceVpsRead(d, m)
+	err = resourceVpsRead(d, m)
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid VPS id: %v", err)
 	}
 
 	d.Set("install_os_template", d.Get("installed_os_template"))
+	d.Set("deletion_protection", false)
+	d.Set("destroy_mode", "hard")
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -612,6 +777,44 @@ func resourceVpsUpgradeV0(ctx context.Context,
 	return rawState, nil
 }
 
+func vpsConnInfo(d *schema.ResourceData, host string) map[string]string {
+	info := map[string]string{
+		"type": "ssh",
+		"host": host,
+	}
+
+	if v, ok := d.GetOk("connection_user"); ok {
+		info["user"] = v.(string)
+	}
+
+	if v, ok := d.GetOk("connection_port"); ok {
+		info["port"] = strconv.Itoa(v.(int))
+	}
+
+	return info
+}
+
+var vpsIpAddressCounts = []struct {
+	attr    string
+	version int
+	role    string
+}{
+	{attr: "public_ipv4_count", version: 4, role: "public_access"},
+	{attr: "private_ipv4_count", version: 4, role: "private_access"},
+	{attr: "public_ipv6_count", version: 6, role: "public_access"},
+}
+
+// getVpsIpAddressCount returns the configured number of IP addresses, or def
+// if the count is not set. Counts which are not set are only read from the API,
+// so that addresses assigned by vpsadmin_ip_address do not cause a diff.
+func getVpsIpAddressCount(d *schema.ResourceData, attr string, def int) int {
+	if v, ok := d.GetOkExists(attr); ok {
+		return v.(int)
+	}
+
+	return def
+}
+
 func hasAnyVpsFeatureChange(d *schema.ResourceData) bool {
 	for _, name := range supportedVpsFeatures {
 		if d.HasChange(fmt.Sprintf("feature_%s", name)) {
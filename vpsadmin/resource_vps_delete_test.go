@@ -0,0 +1,120 @@
+package vpsadmin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
+
+func TestResourceVpsDeleteRespectsDeletionProtection(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	d := newResourceDataWithDiff(
+		t,
+		resourceVps().Schema,
+		"123",
+		map[string]string{
+			"deletion_protection": "true",
+		},
+		nil,
+	)
+
+	err := resourceVpsDelete(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "protected against deletion") {
+		t.Fatalf("resourceVpsDelete() error = %v, want deletion protection error", err)
+	}
+}
+
+func TestResourceVpsDeleteSendsLazyByDestroyMode(t *testing.T) {
+	tests := map[string]string{
+		"soft": `"lazy":true`,
+		"hard": `"lazy":false`,
+	}
+
+	for mode, want := range tests {
+		var deleted bool
+
+		cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v7.0/vpses/123" || r.Method != http.MethodDelete {
+				t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+				http.NotFound(w, r)
+				return
+			}
+
+			if body := readRequestBody(t, r); !strings.Contains(body, want) {
+				t.Errorf("%s: delete body = %s, want %s", mode, body, want)
+			}
+
+			deleted = true
+			writeAPIResponse(t, w, "vps", map[string]interface{}{})
+		})
+
+		d := newResourceDataWithDiff(
+			t,
+			resourceVps().Schema,
+			"123",
+			map[string]string{
+				"destroy_mode": mode,
+			},
+			nil,
+		)
+
+		if err := resourceVpsDelete(d, cfg); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		if !deleted {
+			t.Fatalf("%s: VPS was not deleted", mode)
+		}
+	}
+}
+
+func TestResourceVpsImportRejectsSoftDeletedVps(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/vpses/123" || r.Method != http.MethodGet {
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+			Id:          123,
+			ObjectState: "soft_delete",
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceVps().Schema, "123", nil, nil)
+
+	_, err := resourceVpsImport(d, cfg)
+	if err == nil || !strings.Contains(err.Error(), "restored") {
+		t.Fatalf("resourceVpsImport() error = %v, want restore hint", err)
+	}
+}
+
+func TestResourceVpsReadRemovesDeletedVps(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/vpses/123" {
+			t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		writeAPIResponse(t, w, "vps", &client.ActionVpsShowOutput{
+			Id:          123,
+			ObjectState: "soft_delete",
+		})
+	})
+
+	d := newResourceDataWithDiff(t, resourceVps().Schema, "123", nil, nil)
+
+	if err := resourceVpsRead(d, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("id = %q, want empty", d.Id())
+	}
+}
@@ -18,7 +18,7 @@ func TestResourceSshKeyTrimsKeyState(t *testing.T) {
 	}
 }
 
-func TestResourceVpsIpCountDiffSuppress(t *testing.T) {
+func TestResourceVpsIpCountsAreUpdatable(t *testing.T) {
 	t.Parallel()
 
 	resource := resourceVps()
@@ -32,19 +32,35 @@ func TestResourceVpsIpCountDiffSuppress(t *testing.T) {
 			t.Parallel()
 
 			field := resource.Schema[key]
-			newData := newResourceDataWithDiff(t, resource.Schema, "", nil, nil)
-			if field.DiffSuppressFunc(key, "1", "2", newData) {
-				t.Fatalf("DiffSuppressFunc() before create = true, want false")
+			if field.DiffSuppressFunc != nil {
+				t.Fatalf("DiffSuppressFunc is set, changes after create would be ignored")
 			}
-
-			existingData := newResourceDataWithDiff(t, resource.Schema, "123", nil, nil)
-			if !field.DiffSuppressFunc(key, "1", "2", existingData) {
-				t.Fatalf("DiffSuppressFunc() after create = false, want true")
+			if field.ForceNew {
+				t.Fatalf("ForceNew = true, want false")
+			}
+			if !field.Computed || field.Default != nil {
+				t.Fatalf("Computed = %v, Default = %v, want computed count without default", field.Computed, field.Default)
 			}
 		})
 	}
 }
 
+func TestGetVpsIpAddressCount(t *testing.T) {
+	t.Parallel()
+
+	d := schema.TestResourceDataRaw(t, resourceVps().Schema, map[string]interface{}{
+		"public_ipv4_count": 0,
+	})
+
+	if got := getVpsIpAddressCount(d, "public_ipv4_count", 1); got != 0 {
+		t.Fatalf("public_ipv4_count = %d, want explicit 0", got)
+	}
+
+	if got := getVpsIpAddressCount(d, "public_ipv6_count", 1); got != 1 {
+		t.Fatalf("public_ipv6_count = %d, want default 1", got)
+	}
+}
+
 func TestResourceVpsSshKeysRejectZeroValues(t *testing.T) {
 	t.Parallel()
 
@@ -57,6 +73,27 @@ func TestResourceVpsSshKeysRejectZeroValues(t *testing.T) {
 	}
 }
 
+func TestVpsConnInfo(t *testing.T) {
+	t.Parallel()
+
+	resource := resourceVps()
+
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{})
+	got := vpsConnInfo(d, "2001:db8::10")
+	if len(got) != 2 || got["type"] != "ssh" || got["host"] != "2001:db8::10" {
+		t.Fatalf("vpsConnInfo() = %v, want only type and host", got)
+	}
+
+	d = schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"connection_user": "deploy",
+		"connection_port": 2222,
+	})
+	got = vpsConnInfo(d, "10.0.0.10")
+	if got["user"] != "deploy" || got["port"] != "2222" || got["host"] != "10.0.0.10" {
+		t.Fatalf("vpsConnInfo() = %v", got)
+	}
+}
+
 func TestResourceDatasetExportDiffSuppress(t *testing.T) {
 	t.Parallel()
 
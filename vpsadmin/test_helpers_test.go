@@ -2,10 +2,12 @@ package vpsadmin
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
@@ -30,6 +32,17 @@ func assertQueryValue(t *testing.T, r *http.Request, key string, want string) {
 	}
 }
 
+func readRequestBody(t *testing.T, r *http.Request) string {
+	t.Helper()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
 func writeAPIResponse(t *testing.T, w http.ResponseWriter, key string, value interface{}) {
 	t.Helper()
 
@@ -80,3 +93,19 @@ func newResourceDataWithDiff(
 
 	return d
 }
+
+// testRawConfig returns raw configuration of resource r with values set and
+// other attributes null
+func testRawConfig(r *schema.Resource, values map[string]cty.Value) cty.Value {
+	attrs := make(map[string]cty.Value)
+
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = cty.NullVal(ty)
+		}
+	}
+
+	return cty.ObjectVal(attrs)
+}
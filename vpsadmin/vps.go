@@ -3,6 +3,7 @@ package vpsadmin
 import (
 	"fmt"
 	"github.com/vpsfreecz/vpsadmin-go-client/client"
+	"log"
 )
 
 var supportedVpsFeatures []string = []string{"fuse", "kvm", "lxc", "ppp", "tun"}
@@ -26,6 +27,51 @@ func vpsShow(api *client.Client, id int) (*client.ActionVpsShowOutput, error) {
 	return resp.Output, nil
 }
 
+func getVpsRootDatasetId(api *client.Client, id int) (int64, error) {
+	vps, err := vpsShow(api, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if vps.Dataset == nil {
+		return 0, fmt.Errorf("VPS %d has no root dataset", id)
+	}
+
+	return vps.Dataset.Id, nil
+}
+
+func findVpsIdByHostname(api *client.Client, hostname string) (int64, error) {
+	list := api.Vps.Index.Prepare()
+
+	input := list.NewInput()
+	input.SetHostnameExact(hostname)
+	input.SetLimit(apiPageLimit)
+
+	resp, err := list.Call()
+
+	if err != nil {
+		return 0, err
+	} else if !resp.Status {
+		return 0, fmt.Errorf("Failed to list VPS: %s", resp.Message)
+	}
+
+	var found []int64
+
+	for _, vps := range resp.Output {
+		if vps.Hostname == hostname {
+			found = append(found, vps.Id)
+		}
+	}
+
+	if len(found) == 0 {
+		return 0, fmt.Errorf("VPS with hostname '%s' not found", hostname)
+	} else if len(found) > 1 {
+		return 0, fmt.Errorf("Hostname '%s' is used by multiple VPS: %v", hostname, found)
+	}
+
+	return found[0], nil
+}
+
 func vpsFeatureList(api *client.Client, id int) ([]*client.ActionVpsFeatureIndexOutput, error) {
 	list := api.Vps.Feature.Index.Prepare()
 	list.SetPathParamInt("vps_id", int64(id))
@@ -50,3 +96,73 @@ func isSupportedVpsFeature(feature string) bool {
 
 	return false
 }
+
+func isVpsDeleted(vps *client.ActionVpsShowOutput) bool {
+	return vps.ObjectState == "soft_delete" || vps.ObjectState == "hard_delete"
+}
+
+func deleteVps(api *client.Client, id int64, lazy bool) error {
+	log.Printf("[INFO] Deleting VPS %d (lazy=%t)", id, lazy)
+
+	del := api.Vps.Delete.Prepare()
+	del.SetPathParamInt("vps_id", id)
+
+	input := del.NewInput()
+	input.SetLazy(lazy)
+
+	resp, err := del.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("VPS deletion failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("VPS deletion failed: %v", err)
+	}
+
+	return nil
+}
+
+func startVps(api *client.Client, id int64) error {
+	log.Printf("[INFO] Starting VPS %d", id)
+
+	start := api.Vps.Start.Prepare()
+	start.SetPathParamInt("vps_id", id)
+
+	resp, err := start.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("VPS start failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("VPS start failed: %v", err)
+	}
+
+	return nil
+}
+
+func stopVps(api *client.Client, id int64) error {
+	log.Printf("[INFO] Stopping VPS %d", id)
+
+	stop := api.Vps.Stop.Prepare()
+	stop.SetPathParamInt("vps_id", id)
+
+	resp, err := stop.Call()
+
+	if err != nil {
+		return err
+	} else if !resp.Status {
+		return fmt.Errorf("VPS stop failed: %s", resp.Message)
+	}
+
+	if err := waitForOperation(resp); err != nil {
+		return fmt.Errorf("VPS stop failed: %v", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,13 @@
 package vpsadmin
 
-import "testing"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vpsfreecz/vpsadmin-go-client/client"
+)
 
 func TestIsSupportedVpsFeature(t *testing.T) {
 	t.Parallel()
@@ -32,3 +39,46 @@ func TestIsSupportedVpsFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestFindVpsIdByHostname(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v7.0/vpses" {
+			http.NotFound(w, r)
+			return
+		}
+
+		assertQueryValue(t, r, "vps[limit]", strconv.Itoa(apiPageLimit))
+
+		switch r.URL.Query().Get("vps[hostname_exact]") {
+		case "web1":
+			writeAPIResponse(t, w, "vpses", []*client.ActionVpsIndexOutput{
+				{Id: 123, Hostname: "web1"},
+			})
+		case "web":
+			writeAPIResponse(t, w, "vpses", []*client.ActionVpsIndexOutput{
+				{Id: 124, Hostname: "web"},
+				{Id: 125, Hostname: "web"},
+			})
+		default:
+			writeAPIResponse(t, w, "vpses", []*client.ActionVpsIndexOutput{})
+		}
+	})
+
+	id, err := findVpsIdByHostname(cfg.getClient(), "web1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 123 {
+		t.Fatalf("id = %d, want 123", id)
+	}
+
+	if _, err := findVpsIdByHostname(cfg.getClient(), "web"); err == nil ||
+		!strings.Contains(err.Error(), "multiple") {
+		t.Fatalf("findVpsIdByHostname(web) error = %v, want ambiguity error", err)
+	}
+
+	if _, err := findVpsIdByHostname(cfg.getClient(), "missing"); err == nil ||
+		!strings.Contains(err.Error(), "not found") {
+		t.Fatalf("findVpsIdByHostname(missing) error = %v, want not found", err)
+	}
+}